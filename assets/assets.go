@@ -0,0 +1,307 @@
+/*
+ * Package assets fingerprints static files. Every file gets a URL
+ * containing a hash of its content, so it can be cached forever and a new
+ * deploy changes the URL instead of waiting for caches to expire. Pages
+ * refer to files by their logical name, e.g. "css/style.css", and the
+ * Link, Script and Img builders resolve it and add the SHA-384 integrity
+ * attribute.
+ */
+package assets
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default URL prefix the files are served under
+const DefaultPrefix = "/static/"
+
+// Hex characters of the content hash in fingerprinted names
+const hashLength = 12
+
+// Largest file whose content is kept in memory, so its fingerprinted URL
+// keeps serving the same bytes after the file is edited
+const maxRetained = 1 << 20
+
+/*
+ * Asset
+ */
+type Asset struct {
+	// Logical name, relative to the scanned root, e.g. "css/style.css"
+	Name string `json:"-"`
+	// Fingerprinted name, e.g. "css/style.4be1c9f0a2d3.css"
+	Path string `json:"path"`
+	// Subresource Integrity metadata, e.g. "sha384-..."
+	Integrity string `json:"integrity"`
+	Size      int64  `json:"size"`
+
+	// When the hashed content was last modified, to notice later edits
+	modTime time.Time
+	// The hashed content, nil above maxRetained
+	data []byte
+}
+
+/*
+ * Options
+ */
+type Options struct {
+	// URL path the files are served under, DefaultPrefix when empty
+	Prefix string
+}
+
+/*
+ * Assets is a scanned set of files.
+ */
+type Assets struct {
+	fsys   fs.FS
+	prefix string
+
+	mu     sync.RWMutex
+	byName map[string]Asset
+	byPath map[string]Asset
+}
+
+// New scans fsys
+func New(fsys fs.FS, opts Options) (*Assets, error) {
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	a := &Assets{fsys: fsys, prefix: prefix}
+	if err := a.Rescan(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Dir scans a directory on disk
+func Dir(dir string, opts Options) (*Assets, error) {
+	return New(os.DirFS(dir), opts)
+}
+
+/*
+ * Rescan hashes the files again, picking up changes made since New. Hidden
+ * files and directories are skipped.
+ */
+func (a *Assets) Rescan() error {
+	byName := map[string]Asset{}
+	byPath := map[string]Asset{}
+
+	err := fs.WalkDir(a.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		asset, err := hash(a.fsys, p)
+		if err != nil {
+			return err
+		}
+		byName[asset.Name] = asset
+		byPath[asset.Path] = asset
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	// Earlier versions still in memory keep answering their URLs
+	for p, asset := range a.byPath {
+		if _, ok := byPath[p]; !ok && asset.data != nil {
+			byPath[p] = asset
+		}
+	}
+	a.byName, a.byPath = byName, byPath
+	a.mu.Unlock()
+	return nil
+}
+
+func hash(fsys fs.FS, name string) (Asset, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return Asset{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return Asset{}, err
+	}
+
+	h := sha512.New384()
+	var data []byte
+	var size int64
+	if info.Size() <= maxRetained {
+		if data, err = io.ReadAll(f); err != nil {
+			return Asset{}, err
+		}
+		size, _ = io.Copy(h, bytes.NewReader(data))
+	} else if size, err = io.Copy(h, f); err != nil {
+		return Asset{}, err
+	}
+	sum := h.Sum(nil)
+
+	return Asset{
+		Name:      name,
+		Path:      fingerprint(name, hex.EncodeToString(sum)[:hashLength]),
+		Integrity: "sha384-" + base64.StdEncoding.EncodeToString(sum),
+		Size:      size,
+		modTime:   info.ModTime(),
+		data:      data,
+	}, nil
+}
+
+// changed reports whether the file behind asset was modified after it was
+// hashed
+func (asset Asset) changed(info fs.FileInfo) bool {
+	return !info.ModTime().Equal(asset.modTime) || info.Size() != asset.Size
+}
+
+/*
+ * refresh hashes a file that changed since the last scan under a new
+ * fingerprinted path. The old path keeps its asset: the integrity of pages
+ * rendered before the change only matches the old content.
+ */
+func (a *Assets) refresh(old Asset) (Asset, error) {
+	asset, err := hash(a.fsys, old.Name)
+	if err != nil {
+		return Asset{}, err
+	}
+
+	a.mu.Lock()
+	a.byName[asset.Name] = asset
+	a.byPath[asset.Path] = asset
+	a.mu.Unlock()
+	return asset, nil
+}
+
+// fingerprint inserts the hash before the extension, "app.js" becoming
+// "app.4be1c9f0a2d3.js"
+func fingerprint(name, hash string) string {
+	dir, file := path.Split(name)
+	if i := strings.Index(file, "."); i > 0 {
+		return dir + file[:i] + "." + hash + file[i:]
+	}
+	return dir + file + "." + hash
+}
+
+// Get looks up a file by logical name
+func (a *Assets) Get(name string) (Asset, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	asset, ok := a.byName[strings.TrimPrefix(name, "/")]
+	return asset, ok
+}
+
+/*
+ * URL returns the fingerprinted URL for a logical name. Unknown names are
+ * returned under the prefix as they are, so a missing file shows up as a
+ * 404 rather than a broken page.
+ */
+func (a *Assets) URL(name string) string {
+	if asset, ok := a.Get(name); ok {
+		return a.prefix + asset.Path
+	}
+	return a.prefix + strings.TrimPrefix(name, "/")
+}
+
+// Integrity returns the SHA-384 integrity metadata for a logical name
+func (a *Assets) Integrity(name string) string {
+	asset, _ := a.Get(name)
+	return asset.Integrity
+}
+
+// Manifest maps logical names to their assets, for build tooling and CDNs
+func (a *Assets) Manifest() map[string]Asset {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	m := make(map[string]Asset, len(a.byName))
+	for name, asset := range a.byName {
+		m[name] = asset
+	}
+	return m
+}
+
+// WriteManifest writes the manifest as JSON, keyed by logical name
+func (a *Assets) WriteManifest(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(a.Manifest())
+}
+
+/*
+ * Write copies every file under its fingerprinted name into dir, along
+ * with manifest.json, for serving from a CDN or a static site build.
+ */
+func (a *Assets) Write(dir string) error {
+	m := a.Manifest()
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := a.copy(name, filepath.Join(dir, filepath.FromSlash(m[name].Path))); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	if err := a.WriteManifest(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (a *Assets) copy(name, dst string) error {
+	src, err := a.fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
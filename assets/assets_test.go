@@ -0,0 +1,142 @@
+package assets
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"css/style.css": {Data: []byte("body{}")},
+		".hidden":       {Data: []byte("secret")},
+	}
+	a, err := New(fsys, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url := a.URL("css/style.css")
+	if !strings.HasPrefix(url, "/static/css/style.") || !strings.HasSuffix(url, ".css") || url == "/static/css/style.css" {
+		t.Fatalf("unexpected URL %q", url)
+	}
+	if _, ok := a.Get(".hidden"); ok {
+		t.Fatal("hidden file was scanned")
+	}
+
+	fsys["css/style.css"] = &fstest.MapFile{Data: []byte("body{margin:0}")}
+	if err := a.Rescan(); err != nil {
+		t.Fatal(err)
+	}
+	if a.URL("css/style.css") == url {
+		t.Fatal("URL did not change with the content")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	a, err := New(fstest.MapFS{"app.js": {Data: []byte("1")}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path         string
+		code         int
+		cacheControl string
+	}{
+		{a.URL("app.js"), http.StatusOK, "public, immutable, max-age=31536000"},
+		{"/static/app.js", http.StatusOK, "no-cache"},
+		{"/static/missing.js", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		a.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if w.Code != tt.code {
+			t.Errorf("%s: got status %d, want %d", tt.path, w.Code, tt.code)
+		}
+		if got := w.Header().Get("Cache-Control"); got != tt.cacheControl {
+			t.Errorf("%s: got Cache-Control %q, want %q", tt.path, got, tt.cacheControl)
+		}
+	}
+}
+
+func TestHandlerChangedFile(t *testing.T) {
+	fsys := fstest.MapFS{"app.js": {Data: []byte("1"), ModTime: time.Unix(1, 0)}}
+	a, err := New(fsys, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, integrity := a.URL("app.js"), a.Integrity("app.js")
+
+	fsys["app.js"] = &fstest.MapFile{Data: []byte("22"), ModTime: time.Unix(2, 0)}
+
+	// Pages rendered before the edit still get the bytes their integrity
+	// names, for as long as the URL lives in caches
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		a.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, old, nil))
+		sum := sha512.Sum384(w.Body.Bytes())
+		if w.Code != http.StatusOK || "sha384-"+base64.StdEncoding.EncodeToString(sum[:]) != integrity {
+			t.Fatalf("old URL: got %d %q", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("Cache-Control"); got != "public, immutable, max-age=31536000" {
+			t.Errorf("old fingerprint served with %q", got)
+		}
+	}
+
+	u := a.URL("app.js")
+	if u == old || a.Integrity("app.js") == integrity {
+		t.Fatal("URL did not change with the content")
+	}
+	w := httptest.NewRecorder()
+	a.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, u, nil))
+	if w.Body.String() != "22" {
+		t.Errorf("new URL: got %q", w.Body.String())
+	}
+	if got := w.Header().Get("Cache-Control"); got != "public, immutable, max-age=31536000" {
+		t.Errorf("new fingerprint served with %q", got)
+	}
+
+	fsys["app.js"] = &fstest.MapFile{Data: []byte("333"), ModTime: time.Unix(3, 0)}
+	w = httptest.NewRecorder()
+	a.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/static/app.js", nil))
+	if w.Body.String() != "333" || w.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("logical name: got %q with %q", w.Body.String(), w.Header().Get("Cache-Control"))
+	}
+
+	// A rescan forgets nothing still in memory
+	if err := a.Rescan(); err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	a.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, old, nil))
+	if w.Body.String() != "1" {
+		t.Errorf("old URL after rescan: got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestWrite(t *testing.T) {
+	a, err := New(fstest.MapFS{"css/style.css": {Data: []byte("body{}")}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := a.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	asset, _ := a.Get("css/style.css")
+	for _, name := range []string{filepath.FromSlash(asset.Path), "manifest.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package assets
+
+import (
+	"github.com/bitpartio/Mx/elements"
+)
+
+/*
+ * Link renders a <link> to the named asset, stylesheet unless props.Rel
+ * says otherwise, with its integrity for stylesheets, preloads and module
+ * preloads.
+ */
+func (a *Assets) Link(name string, props elements.LinkProps) string {
+	if len(props.Rel) == 0 {
+		props.Rel = elements.LinkRelOptions{elements.LinkOptions.Rel.Stylesheet}
+	}
+	props.Href = a.URL(name)
+
+	if props.Integrity == "" {
+		for _, rel := range props.Rel {
+			switch rel().String() {
+			case "stylesheet", "preload", "modulepreload":
+				props.Integrity = a.Integrity(name)
+			}
+		}
+	}
+
+	return elements.Link(props)
+}
+
+// Stylesheet renders <link rel="stylesheet"> for the named asset
+func (a *Assets) Stylesheet(name string) string {
+	return a.Link(name, elements.LinkProps{})
+}
+
+// Script renders a <script> loading the named asset, with its integrity
+func (a *Assets) Script(name string, props elements.ScriptProps) string {
+	props.Src = a.URL(name)
+	if props.Integrity == "" {
+		props.Integrity = a.Integrity(name)
+	}
+	return elements.Script(props)
+}
+
+// Img renders an <img> of the named asset; images take no integrity
+func (a *Assets) Img(name string, props elements.ImgProps) string {
+	props.Src = a.URL(name)
+	return elements.Img(props)
+}
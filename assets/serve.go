@@ -0,0 +1,96 @@
+package assets
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/bitpartio/Mx/httpcache"
+)
+
+/*
+ * Handler serves the files under the prefix. Fingerprinted URLs are
+ * cached as immutable; logical names still work, for references the
+ * builders did not produce, but are revalidated on every request. A file
+ * edited since it was scanned is hashed again under a new URL, while its
+ * old URL keeps serving the old content, which the integrity of pages
+ * already rendered expects. Only files up to 1 MiB are kept for that; the
+ * old URL of a larger file answers 404.
+ */
+func (a *Assets) Handler() http.Handler {
+	return http.StripPrefix(strings.TrimSuffix(a.prefix, "/"), a)
+}
+
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	p := strings.TrimPrefix(r.URL.Path, "/")
+
+	a.mu.RLock()
+	asset, fingerprinted := a.byPath[p]
+	if !fingerprinted {
+		asset = a.byName[p]
+	}
+	current := a.byName[asset.Name].Path == asset.Path
+	a.mu.RUnlock()
+
+	if asset.Name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var content io.ReadSeeker
+	if f, err := a.fsys.Open(asset.Name); err == nil {
+		defer f.Close()
+		info, err := f.Stat()
+		switch {
+		case err != nil:
+		case !asset.changed(info):
+			content = readSeeker(f)
+		case current:
+			newer, err := a.refresh(asset)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if !fingerprinted {
+				asset = newer
+				content = readSeeker(f)
+			}
+		}
+	}
+	// A fingerprinted path whose file changed keeps the content it was
+	// hashed from
+	if content == nil && asset.data != nil {
+		content = bytes.NewReader(asset.data)
+	}
+	if content == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	policy := httpcache.Revalidate
+	if fingerprinted {
+		policy = httpcache.Immutable
+	}
+	w.Header().Set("Cache-Control", policy.String())
+	w.Header().Set("ETag", `"`+asset.Integrity[len("sha384-"):len("sha384-")+24]+`"`)
+
+	http.ServeContent(w, r, asset.Name, asset.modTime, content)
+}
+
+// readSeeker avoids reading files whose fs.FS already supports seeking,
+// such as os.DirFS and embed.FS
+func readSeeker(f fs.File) io.ReadSeeker {
+	if rs, ok := f.(io.ReadSeeker); ok {
+		return rs
+	}
+	b, _ := io.ReadAll(f)
+	return bytes.NewReader(b)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/bitpartio/Mx/ssg"
+)
+
+func init() {
+	commands = append(commands, command{
+		name:  "build",
+		usage: "render a static site into an output directory",
+		run:   build,
+	})
+}
+
+/*
+ * Runs the site program with the ssg environment set, which makes
+ * ssg.Site.Main render every route and exit instead of serving.
+ */
+func build(args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	out := fs.String("out", "dist", "output directory")
+	static := fs.String("static", "", "directory of static assets to copy")
+	baseURL := fs.String("base-url", "", "absolute site URL, enables sitemap.xml")
+	fs.Parse(args)
+
+	pkg := "."
+	if fs.NArg() > 0 {
+		pkg = fs.Arg(0)
+	}
+
+	outDir, err := filepath.Abs(*out)
+	if err != nil {
+		return err
+	}
+	env := append(os.Environ(),
+		ssg.EnvOut+"="+outDir,
+		ssg.EnvBaseURL+"="+*baseURL,
+	)
+	if *static != "" {
+		staticDir, err := filepath.Abs(*static)
+		if err != nil {
+			return err
+		}
+		env = append(env, ssg.EnvStatic+"="+staticDir)
+	}
+
+	cmd := exec.Command("go", "run", pkg)
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bitpartio/Mx/livereload"
+)
+
+func init() {
+	commands = append(commands, command{
+		name:  "dev",
+		usage: "rebuild and restart on changes, with live reload",
+		run:   dev,
+	})
+}
+
+/*
+ * Builds and runs the program, restarting it whenever a watched file
+ * changes. Browsers talk to a proxy in front of the program which injects
+ * the live-reload script into HTML pages and tells them to reload once
+ * the new process accepts connections.
+ */
+func dev(args []string) error {
+	fs := flag.NewFlagSet("dev", flag.ExitOnError)
+	addr := fs.String("addr", ":3000", "address the development proxy listens on")
+	target := fs.String("proxy", "http://localhost:8000", "address the program listens on")
+	watch := fs.String("watch", ".", "directory tree to watch")
+	exts := fs.String("ext", ".go,.html,.tmpl,.gohtml", "comma separated file extensions to watch")
+	interval := fs.Duration("interval", 500*time.Millisecond, "polling interval")
+	fs.Parse(args)
+
+	pkg := "."
+	if fs.NArg() > 0 {
+		pkg = fs.Arg(0)
+	}
+
+	upstream, err := url.Parse(*target)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "mx-dev")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	r := &runner{
+		pkg:      pkg,
+		bin:      filepath.Join(tmp, "app"),
+		upstream: upstream,
+		reload:   livereload.New(),
+	}
+
+	w := newWatcher(*watch, strings.Split(*exts, ","))
+
+	// Returned rather than fatal, so the program is stopped and the
+	// temporary directory removed
+	serveErr := make(chan error, 1)
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle(livereload.Path, r.reload)
+		mux.Handle("/", r.proxy())
+		log.Printf("mx dev: serving on %s, proxying %s", *addr, upstream)
+		serveErr <- http.ListenAndServe(*addr, mux)
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	r.restart()
+	for {
+		select {
+		case <-interrupt:
+			r.stop()
+			return nil
+		case err := <-serveErr:
+			r.stop()
+			return fmt.Errorf("mx dev: %w", err)
+		case <-time.After(*interval):
+			if changed := w.scan(); changed != "" {
+				log.Printf("mx dev: %s changed", changed)
+				r.restart()
+			}
+		}
+	}
+}
+
+/*
+ * Runner
+ */
+type runner struct {
+	pkg      string
+	bin      string
+	upstream *url.URL
+	reload   *livereload.Server
+	cmd      *exec.Cmd
+}
+
+func (r *runner) restart() {
+	var out bytes.Buffer
+	build := exec.Command("go", "build", "-o", r.bin, r.pkg)
+	build.Stdout = &out
+	build.Stderr = &out
+	if err := build.Run(); err != nil {
+		// Keep the previous process serving until the build is fixed
+		log.Printf("mx dev: build failed\n%s", out.String())
+		r.reload.Error(out.String())
+		return
+	}
+
+	r.stop()
+
+	r.cmd = exec.Command(r.bin)
+	r.cmd.Env = append(os.Environ(), "MX_DEV=1")
+	r.cmd.Stdout = os.Stdout
+	r.cmd.Stderr = os.Stderr
+	if err := r.cmd.Start(); err != nil {
+		log.Printf("mx dev: %v", err)
+		r.cmd = nil
+		return
+	}
+
+	if r.waitReady(10 * time.Second) {
+		r.reload.Reload()
+	}
+}
+
+func (r *runner) stop() {
+	if r.cmd == nil || r.cmd.Process == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.cmd.Wait()
+		close(done)
+	}()
+
+	if err := r.cmd.Process.Signal(os.Interrupt); err != nil {
+		r.cmd.Process.Kill()
+	}
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		r.cmd.Process.Kill()
+		<-done
+	}
+	r.cmd = nil
+}
+
+// waitReady polls the program's address until it accepts connections
+func (r *runner) waitReady(timeout time.Duration) bool {
+	host := r.upstream.Host
+	if r.upstream.Port() == "" {
+		host = net.JoinHostPort(r.upstream.Hostname(), "80")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", host, 200*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	log.Printf("mx dev: %s did not start listening within %s", host, timeout)
+	return false
+}
+
+func (r *runner) proxy() http.Handler {
+	p := httputil.NewSingleHostReverseProxy(r.upstream)
+
+	director := p.Director
+	p.Director = func(req *http.Request) {
+		director(req)
+		// Pages must arrive uncompressed so the script can be injected
+		req.Header.Del("Accept-Encoding")
+	}
+
+	p.ModifyResponse = func(res *http.Response) error {
+		if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/html") {
+			return nil
+		}
+		// htmx swaps fragments into a page that already has the script
+		if res.Request.Header.Get("HX-Request") != "" {
+			return nil
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+		body = livereload.Inject(body)
+
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		res.ContentLength = int64(len(body))
+		res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		res.Header.Del("ETag")
+		return nil
+	}
+
+	p.ErrorHandler = func(w http.ResponseWriter, req *http.Request, err error) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintf(w, "<p>mx dev: %s is not responding, waiting for it to restart</p>", r.upstream)
+		w.Write([]byte(livereload.Tag()))
+	}
+
+	return p
+}
+
+/*
+ * Watcher polls the project tree for modified, added or removed files,
+ * which needs no platform specific notification APIs.
+ */
+type watcher struct {
+	root  string
+	exts  map[string]bool
+	state map[string]time.Time
+}
+
+func newWatcher(root string, exts []string) *watcher {
+	w := &watcher{root: root, exts: map[string]bool{}}
+	for _, ext := range exts {
+		ext = strings.TrimSpace(ext)
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		w.exts[ext] = true
+	}
+	w.state = w.snapshot()
+	return w
+}
+
+// scan returns a changed path, or "" when nothing changed
+func (w *watcher) scan() string {
+	next := w.snapshot()
+	defer func() { w.state = next }()
+
+	for p, mod := range next {
+		if prev, ok := w.state[p]; !ok || !prev.Equal(mod) {
+			return p
+		}
+	}
+	for p := range w.state {
+		if _, ok := next[p]; !ok {
+			return p
+		}
+	}
+	return ""
+}
+
+func (w *watcher) snapshot() map[string]time.Time {
+	state := map[string]time.Time{}
+	filepath.Walk(w.root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if p != w.root && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if w.exts[filepath.Ext(name)] {
+			state[p] = info.ModTime()
+		}
+		return nil
+	})
+	return state
+}
@@ -0,0 +1,61 @@
+// Command mx is the Mx development tool.
+//
+// Usage:
+//
+//	mx build [flags] [package]   render a static site into an output directory
+//	mx dev [flags] [package]     rebuild and restart on changes, with live reload
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands []command
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: mx <command> [flags] [package]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		if err := c.run(os.Args[2:]); err != nil {
+			// The child program has already reported its own failure
+			var exit *exec.ExitError
+			if errors.As(err, &exit) {
+				os.Exit(exit.ExitCode())
+			}
+			fmt.Fprintln(os.Stderr, "mx "+name+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if name != "help" && name != "-h" && name != "--help" {
+		fmt.Fprintf(os.Stderr, "mx: unknown command %q\n\n", name)
+	}
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,111 @@
+/*
+ * Package component gives reusable pieces of markup a common shape:
+ * anything that renders itself to a writer given the request context.
+ * Components compose through props, named slots and default children,
+ * and since an Instance keeps its definition, props and slots, they can be
+ * inspected in tests, cached and rendered on their own.
+ *
+ *	var Card = component.Def[CardProps]{
+ *		Name: "card",
+ *		Render: func(ctx context.Context, w io.Writer, props CardProps, slots component.Slots) error {
+ *			header, err := slots.String(ctx, "header", component.Text(props.Title))
+ *			if err != nil {
+ *				return err
+ *			}
+ *			body, err := slots.String(ctx, component.DefaultSlot, nil)
+ *			if err != nil {
+ *				return err
+ *			}
+ *			_, err = io.WriteString(w, Article(ArticleProps{InnerHTML: Header(HeaderProps{InnerHTML: header}) + body}))
+ *			return err
+ *		},
+ *	}
+ *
+ *	page := Card.New(CardProps{Title: "Orders"}, component.HTML(table)).
+ *		Slot("header", component.HTML(H2(H2Props{InnerHTML: "Orders"})))
+ */
+package component
+
+import (
+	"context"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+)
+
+/*
+ * Component renders markup to w. ctx carries the request's data, as put
+ * there by middleware.
+ */
+type Component interface {
+	Render(ctx context.Context, w io.Writer) error
+}
+
+// Func adapts a function to Component
+type Func func(ctx context.Context, w io.Writer) error
+
+func (f Func) Render(ctx context.Context, w io.Writer) error {
+	return f(ctx, w)
+}
+
+// HTML is markup already built, such as the output of an elements builder
+type HTML string
+
+func (h HTML) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, string(h))
+	return err
+}
+
+// Text renders s escaped
+func Text(s string) Component {
+	return HTML(html.EscapeString(s))
+}
+
+// Group renders components one after the other
+type Group []Component
+
+func (g Group) Render(ctx context.Context, w io.Writer) error {
+	for _, c := range g {
+		if c == nil {
+			continue
+		}
+		if err := c.Render(ctx, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+ * String renders c to a string, for the InnerHTML of elements builders
+ * and for tests
+ */
+func String(ctx context.Context, c Component) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	if h, ok := c.(HTML); ok {
+		return string(h), nil
+	}
+	var b strings.Builder
+	err := c.Render(ctx, &b)
+	return b.String(), err
+}
+
+/*
+ * Handler serves c as an HTML page or fragment, rendered with the
+ * request's context. Output is buffered so a failed render can still be
+ * answered with an error status.
+ */
+func Handler(c Component) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := String(r.Context(), c)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, s)
+	})
+}
@@ -0,0 +1,72 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/bitpartio/Mx/memo"
+)
+
+type cardProps struct {
+	Title string
+}
+
+var card = Def[cardProps]{
+	Name: "card",
+	Render: func(ctx context.Context, w io.Writer, props cardProps, slots Slots) error {
+		io.WriteString(w, "<article><header>")
+		if err := slots.Render(ctx, w, "header", Text(props.Title)); err != nil {
+			return err
+		}
+		io.WriteString(w, "</header>")
+		if err := slots.Render(ctx, w, DefaultSlot, nil); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "</article>")
+		return err
+	},
+}
+
+func TestSlots(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := String(ctx, card.New(cardProps{Title: "A & B"}, HTML("<p>1</p>"), HTML("<p>2</p>")))
+	if err != nil || s != "<article><header>A &amp; B</header><p>1</p><p>2</p></article>" {
+		t.Fatalf("got %q, %v", s, err)
+	}
+
+	s, _ = String(ctx, card.New(cardProps{}).Slot("header", HTML("<h2>Orders</h2>")))
+	if s != "<article><header><h2>Orders</h2></header></article>" {
+		t.Fatalf("got %q", s)
+	}
+}
+
+func TestCached(t *testing.T) {
+	cache := memo.New(memo.Options{})
+	fail := errors.New("fail")
+	calls := 0
+	def := Def[int]{Name: "n", Render: func(ctx context.Context, w io.Writer, n int, _ Slots) error {
+		calls++
+		if calls == 1 {
+			io.WriteString(w, "partial")
+			return fail
+		}
+		_, err := io.WriteString(w, "ok")
+		return err
+	}}
+
+	c := Cached(cache, def.New(1))
+	if _, err := String(context.Background(), c); err != fail {
+		t.Fatalf("got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if s, err := String(context.Background(), c); err != nil || s != "ok" {
+			t.Fatalf("got %q, %v", s, err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("rendered %d times", calls)
+	}
+}
@@ -0,0 +1,112 @@
+package component
+
+import (
+	"context"
+	"io"
+
+	"github.com/bitpartio/Mx/memo"
+)
+
+// Name of the slot holding the children given to New
+const DefaultSlot = ""
+
+/*
+ * Slots hold the content passed into a component by name, like the
+ * named <slot> elements of a web component's shadow tree
+ */
+type Slots map[string]Component
+
+// Has reports whether the slot was filled
+func (s Slots) Has(name string) bool {
+	return s[name] != nil
+}
+
+/*
+ * Render writes the slot's content, or fallback when it was not filled,
+ * as <slot> shows its own children
+ */
+func (s Slots) Render(ctx context.Context, w io.Writer, name string, fallback Component) error {
+	c := s[name]
+	if c == nil {
+		c = fallback
+	}
+	if c == nil {
+		return nil
+	}
+	return c.Render(ctx, w)
+}
+
+// String is Render to a string
+func (s Slots) String(ctx context.Context, name string, fallback Component) (string, error) {
+	c := s[name]
+	if c == nil {
+		c = fallback
+	}
+	return String(ctx, c)
+}
+
+/*
+ * Def defines a kind of component with props P. Name identifies it in
+ * tests and cache keys.
+ */
+type Def[P any] struct {
+	Name   string
+	Render func(ctx context.Context, w io.Writer, props P, slots Slots) error
+}
+
+/*
+ * New makes a component of the kind with props, its children filling the
+ * default slot
+ */
+func (d Def[P]) New(props P, children ...Component) *Instance[P] {
+	i := &Instance[P]{Def: d, Props: props, Slots: Slots{}}
+	switch len(children) {
+	case 0:
+	case 1:
+		i.Slots[DefaultSlot] = children[0]
+	default:
+		i.Slots[DefaultSlot] = Group(children)
+	}
+	return i
+}
+
+/*
+ * A component of a Def, with the props and slots it renders
+ */
+type Instance[P any] struct {
+	Def   Def[P]
+	Props P
+	Slots Slots
+}
+
+// Slot fills the named slot, returning the instance for chaining
+func (i *Instance[P]) Slot(name string, c Component) *Instance[P] {
+	if i.Slots == nil {
+		i.Slots = Slots{}
+	}
+	i.Slots[name] = c
+	return i
+}
+
+func (i *Instance[P]) Render(ctx context.Context, w io.Writer) error {
+	return i.Def.Render(ctx, w, i.Props, i.Slots)
+}
+
+/*
+ * Cached serves the instance from cache, keyed by its Def's Name and its
+ * props. Only cache components whose output depends on their props
+ * alone, not on slots or the request context. Failed renders, with
+ * whatever they wrote before failing, are never stored.
+ */
+func Cached[P any](cache *memo.Cache, i *Instance[P], tags ...string) Component {
+	return Func(func(ctx context.Context, w io.Writer) error {
+		s, err := cache.MemoErr(i.Def.Name, i.Props, func() (string, error) {
+			return String(ctx, i)
+		}, tags...)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, s)
+		return err
+	})
+}
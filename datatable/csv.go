@@ -0,0 +1,56 @@
+package datatable
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+/*
+ * CSV writes the query's view of rows with a header row, using each
+ * column's Text. Cells other than numbers that spreadsheets would run as
+ * formulas are prefixed with a quote.
+ */
+func (t DataTable[T]) CSV(w io.Writer, rows []T, q Query) error {
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		record[i] = defuse(c.Header)
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for _, row := range t.Apply(rows, q) {
+		for i, c := range t.Columns {
+			record[i] = ""
+			if c.Text != nil {
+				record[i] = defuse(c.Text(row))
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Ref: https://owasp.org/www-community/attacks/CSV_Injection
+func defuse(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '+', '-':
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return s
+		}
+		return "'" + s
+	case '=', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,226 @@
+/*
+ * Package datatable renders slices of structs as sortable, filterable HTML
+ * tables driven by htmx, parses the query parameters those tables send and
+ * exports the same view as CSV.
+ *
+ *	var people = datatable.DataTable[Person]{
+ *		ID:     "people",
+ *		URL:    "/people",
+ *		Export: "Export CSV",
+ *		Columns: []datatable.Column[Person]{
+ *			{Key: "name", Header: "Name", Text: func(p Person) string { return p.Name },
+ *				Less: func(a, b Person) bool { return a.Name < b.Name }, Filter: true},
+ *			{Key: "age", Header: "Age", Text: func(p Person) string { return strconv.Itoa(p.Age) },
+ *				Less: func(a, b Person) bool { return a.Age < b.Age }},
+ *		},
+ *	}
+ *
+ *	http.Handle("/people", people.Handler(loadPeople))
+ */
+package datatable
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+/*
+ * A column of a DataTable
+ */
+type Column[T any] struct {
+	// Identifies the column in query parameters
+	Key    string
+	Header string
+	// Short form of Header, read by screen readers
+	Abbr string
+	// Plain text of the cell, used for CSV export, filtering and, escaped,
+	// as the cell when Cell is nil
+	Text func(row T) string
+	// HTML of the cell
+	Cell func(row T) string
+	// Orders rows ascending; the column is not sortable when nil
+	Less func(a, b T) bool
+	// Adds a search input keeping rows whose Text contains its value,
+	// ignoring case
+	Filter bool
+	// Renders the cells as <th scope="row">, labelling their row
+	RowHeader bool
+}
+
+/*
+ * A table over a slice of T. Sorting and filtering request URL with the
+ * parameters Parse reads and swap the result in place.
+ */
+type DataTable[T any] struct {
+	// Element ID of the table, required for htmx targeting
+	ID      string
+	Caption string
+	Columns []Column[T]
+	// Serves the table, usually through Handler
+	URL string
+	// Prepended to query parameters, for pages with several tables
+	Prefix string
+	// Shown in a single row when no rows match
+	Empty string
+	// Text of a link in the footer downloading the current view as CSV,
+	// e.g. "Export CSV"; no link when empty
+	Export string
+}
+
+/*
+ * The view requested of a table
+ */
+type Query struct {
+	// Key of the sorted column, none when empty
+	Sort string
+	Desc bool
+	// Filter values by column key
+	Filters map[string]string
+}
+
+func (t DataTable[T]) param(name string) string {
+	return t.Prefix + name
+}
+
+func (t DataTable[T]) filterParam(key string) string {
+	return t.Prefix + "filter." + key
+}
+
+func (t DataTable[T]) column(key string) (Column[T], bool) {
+	for _, c := range t.Columns {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Column[T]{}, false
+}
+
+/*
+ * Parse reads a Query from URL parameters, ignoring unknown or unsortable
+ * columns and empty filters:
+ *
+ *	?sort=age&order=desc&filter.name=ada
+ */
+func (t DataTable[T]) Parse(values url.Values) Query {
+	q := Query{Filters: map[string]string{}}
+
+	if key := values.Get(t.param("sort")); key != "" {
+		if c, ok := t.column(key); ok && c.Less != nil {
+			q.Sort = key
+			q.Desc = values.Get(t.param("order")) == "desc"
+		}
+	}
+
+	for _, c := range t.Columns {
+		if !c.Filter {
+			continue
+		}
+		if v := strings.TrimSpace(values.Get(t.filterParam(c.Key))); v != "" {
+			q.Filters[c.Key] = v
+		}
+	}
+
+	return q
+}
+
+// ParseRequest is Parse for the request's URL
+func (t DataTable[T]) ParseRequest(r *http.Request) Query {
+	return t.Parse(r.URL.Query())
+}
+
+// Encode is the inverse of Parse
+func (t DataTable[T]) Encode(q Query) url.Values {
+	values := url.Values{}
+	if q.Sort != "" {
+		values.Set(t.param("sort"), q.Sort)
+		if q.Desc {
+			values.Set(t.param("order"), "desc")
+		} else {
+			values.Set(t.param("order"), "asc")
+		}
+	}
+	for key, v := range q.Filters {
+		if v != "" {
+			values.Set(t.filterParam(key), v)
+		}
+	}
+	return values
+}
+
+/*
+ * Apply returns the rows matching the query's filters in its order. rows is
+ * left untouched; the sort is stable.
+ */
+func (t DataTable[T]) Apply(rows []T, q Query) []T {
+	out := make([]T, 0, len(rows))
+	for _, row := range rows {
+		if t.match(row, q.Filters) {
+			out = append(out, row)
+		}
+	}
+
+	if c, ok := t.column(q.Sort); ok && c.Less != nil {
+		less := c.Less
+		if q.Desc {
+			less = func(a, b T) bool { return c.Less(b, a) }
+		}
+		sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	}
+
+	return out
+}
+
+func (t DataTable[T]) match(row T, filters map[string]string) bool {
+	for key, v := range filters {
+		c, ok := t.column(key)
+		if !ok || !c.Filter || c.Text == nil {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(c.Text(row)), strings.ToLower(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+ * Handler serves the table over the rows load returns, as HTML or, with
+ * ?format=csv, as a CSV download.
+ */
+func (t DataTable[T]) Handler(load func(r *http.Request) ([]T, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rows, err := load(r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		q := t.ParseRequest(r)
+
+		if r.URL.Query().Get(t.param("format")) == "csv" {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="`+t.exportName()+`"`)
+			t.CSV(w, rows, q)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(t.Render(rows, q)))
+	})
+}
+
+// ExportURL links to the CSV export of the query's view
+func (t DataTable[T]) ExportURL(q Query) string {
+	values := t.Encode(q)
+	values.Set(t.param("format"), "csv")
+	return t.URL + "?" + values.Encode()
+}
+
+// exportName is the file name of the CSV export
+func (t DataTable[T]) exportName() string {
+	if t.ID == "" {
+		return "export.csv"
+	}
+	return t.ID + ".csv"
+}
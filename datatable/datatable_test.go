@@ -0,0 +1,122 @@
+package datatable
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type person struct {
+	Name string
+	Age  int
+}
+
+var people = DataTable[person]{
+	ID:  "people",
+	URL: "/people",
+	Columns: []Column[person]{
+		{
+			Key: "name", Header: "Name", Filter: true,
+			Text: func(p person) string { return p.Name },
+			Less: func(a, b person) bool { return a.Name < b.Name },
+		},
+		{
+			Key: "age", Header: "Age",
+			Text: func(p person) string { return strconv.Itoa(p.Age) },
+			Less: func(a, b person) bool { return a.Age < b.Age },
+		},
+	},
+}
+
+var rows = []person{{"Bob", 30}, {"Ada", 36}, {"Alan", 41}, {"=HYPERLINK()", -1}}
+
+func TestQuery(t *testing.T) {
+	q := people.Parse(url.Values{"sort": {"age"}, "order": {"desc"}, "filter.name": {"A"}, "filter.age": {"3"}})
+	if q.Sort != "age" || !q.Desc || len(q.Filters) != 1 {
+		t.Fatalf("unexpected %+v", q)
+	}
+	if got := people.Parse(people.Encode(q)); got.Sort != q.Sort || got.Desc != q.Desc || got.Filters["name"] != "A" {
+		t.Fatalf("round trip gave %+v", got)
+	}
+
+	out := people.Apply(rows, q)
+	if len(out) != 2 || out[0].Name != "Alan" || out[1].Name != "Ada" {
+		t.Fatalf("unexpected rows %+v", out)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var b strings.Builder
+	if err := people.CSV(&b, rows, Query{Sort: "name"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "Name,Age\n'=HYPERLINK(),-1\nAda,36\nAlan,41\nBob,30\n"
+	if b.String() != want {
+		t.Fatalf("got %q", b.String())
+	}
+}
+
+// Collapses the builders' padding to single spaces
+func squash(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestRender(t *testing.T) {
+	table := people
+	table.Caption = "People & co"
+	table.Export = "Export CSV"
+	table.Columns = append([]Column[person](nil), people.Columns...)
+	table.Columns[0].RowHeader = true
+	table.Columns[1].Abbr = "Yrs"
+
+	got := squash(table.Render(rows, Query{Sort: "age", Desc: true, Filters: map[string]string{"name": "a"}}))
+	for _, want := range []string{
+		`<table id="people" class="datatable" ><caption >People &amp; co</caption>`,
+		// Unsorted columns start ascending, keeping the filters
+		`<th scope="col" ><a hx-get="/people" hx-include="#people .datatable-filters input[type=search]" hx-push-url="true" hx-swap="outerHTML" hx-target="#people" hx-vals="{&#34;order&#34;:&#34;asc&#34;,&#34;sort&#34;:&#34;name&#34;}" href="/people?filter.name=a&amp;order=asc&amp;sort=name" >Name</a></th>`,
+		// The sorted column says so and toggles
+		`<th abbr="Yrs" aria-sort="descending" scope="col" >`,
+		`href="/people?filter.name=a&amp;order=asc&amp;sort=age" >Age</a>`,
+		// Filters carry the sort and swap the body and export link
+		`<input name="sort" type="hidden" value="age" /><input name="order" type="hidden" value="desc" />`,
+		`<input aria-label="Filter Name" hx-get="/people" hx-include="#people .datatable-filters input" hx-push-url="true" hx-select="#people tbody" hx-select-oob="#people-export" hx-swap="outerHTML" hx-target="#people tbody" hx-trigger="input changed delay:300ms, search" name="filter.name" type="search" value="a" />`,
+		`<tbody ><tr ><th scope="row" >Alan</th><td >41</td></tr><tr ><th scope="row" >Ada</th><td >36</td></tr></tbody>`,
+		`<tfoot ><tr ><td colspan="2" ><a download="people.csv" id="people-export" hx-boost="false" href="/people?filter.name=a&amp;format=csv&amp;order=desc&amp;sort=age" >Export CSV</a></td></tr></tfoot>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	if strings.Contains(got, "Bob") || strings.Count(got, "aria-sort") != 1 {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	table := people
+	table.Empty = "No <people>"
+	table.Columns = []Column[person]{
+		{Key: "name", Header: "Name", Cell: func(p person) string { return "<b>" + p.Name + "</b>" }},
+		{Key: "age", Header: "Age"},
+	}
+
+	got := squash(table.Render(nil, Query{}))
+	for _, want := range []string{
+		`<th scope="col" >Name</th>`,
+		`<tbody ><tr ><td colspan="2" class="datatable-empty" >No &lt;people&gt;</td></tr></tbody>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"datatable-filters", "<a ", "tfoot", "hx-"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("rendered %s in %s", unwanted, got)
+		}
+	}
+
+	if got := squash(table.Render([]person{{Name: "Ada"}}, Query{})); !strings.Contains(got, `<tr ><td ><b>Ada</b></td><td ></td></tr>`) {
+		t.Errorf("cell: %s", got)
+	}
+}
@@ -0,0 +1,211 @@
+package datatable
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * Render builds the table for the query's view of rows:
+ *
+ *	<table id="people" class="datatable">
+ *		<thead>
+ *			<tr><th scope="col" aria-sort="ascending"><a href="..." hx-get="...">Name</a></th>...</tr>
+ *			<tr class="datatable-filters"><td><input type="search" name="filter.name" ...></td>...</tr>
+ *		</thead>
+ *		<tbody>...</tbody>
+ *		<tfoot><tr><td colspan="2"><a id="people-export" href="/people?format=csv" ...>Export CSV</a></td></tr></tfoot>
+ *	</table>
+ *
+ * Sorting links fall back to plain navigation without htmx; with it they
+ * swap the whole table. Filter inputs swap only the <tbody>, keeping focus
+ * while typing, and the export link out of band, so it always downloads
+ * the view on screen. Both push the view's URL to the history.
+ */
+func (t DataTable[T]) Render(rows []T, q Query) string {
+	var headers strings.Builder
+	for _, c := range t.Columns {
+		headers.WriteString(t.header(c, q))
+	}
+	thead := elements.Tr(elements.TrProps{InnerHTML: headers.String()})
+	if t.filterable() {
+		thead += t.filters(q)
+	}
+
+	var caption string
+	if t.Caption != "" {
+		caption = elements.Caption(elements.CaptionProps{InnerHTML: html.EscapeString(t.Caption)})
+	}
+
+	return elements.Table(elements.TableProps{
+		GlobalProps: elements.GlobalProps{ID: t.ID, Class: []string{"datatable"}},
+		InnerHTML: caption +
+			elements.Thead(elements.TheadProps{InnerHTML: thead}) +
+			t.body(t.Apply(rows, q)) +
+			t.footer(q),
+	})
+}
+
+// footer renders the export link, when the table has one
+func (t DataTable[T]) footer(q Query) string {
+	if t.Export == "" {
+		return ""
+	}
+	span := len(t.Columns)
+	link := elements.A(elements.AProps{
+		GlobalProps: elements.GlobalProps{
+			ID:   t.exportID(),
+			Htmx: utils.HtmxProps{"boost": "false"},
+		},
+		Download:  html.EscapeString(t.exportName()),
+		Href:      html.EscapeString(t.ExportURL(q)),
+		InnerHTML: html.EscapeString(t.Export),
+	})
+	return elements.Tfoot(elements.TfootProps{InnerHTML: elements.Tr(elements.TrProps{
+		InnerHTML: elements.Td(elements.TdProps{Colspan: &span, InnerHTML: link}),
+	})})
+}
+
+func (t DataTable[T]) exportID() string {
+	return t.ID + "-export"
+}
+
+func (t DataTable[T]) filterable() bool {
+	for _, c := range t.Columns {
+		if c.Filter {
+			return true
+		}
+	}
+	return false
+}
+
+func (t DataTable[T]) header(c Column[T], q Query) string {
+	props := elements.ThProps{
+		Abbr:      html.EscapeString(c.Abbr),
+		Scope:     elements.ThOptions.Scope.Col,
+		InnerHTML: html.EscapeString(c.Header),
+	}
+	if c.Less == nil {
+		return elements.Th(props)
+	}
+
+	sorted := q.Sort == c.Key
+	if sorted {
+		sort := "ascending"
+		if q.Desc {
+			sort = "descending"
+		}
+		props.Aria = utils.AriaRoles{"sort": sort}
+	}
+
+	// Sorted columns toggle, others start ascending
+	next := Query{Sort: c.Key, Desc: sorted && !q.Desc, Filters: q.Filters}
+	order := "asc"
+	if next.Desc {
+		order = "desc"
+	}
+	vals, _ := json.Marshal(map[string]string{t.param("sort"): c.Key, t.param("order"): order})
+
+	props.InnerHTML = elements.A(elements.AProps{
+		GlobalProps: elements.GlobalProps{Htmx: utils.HtmxProps{
+			"get":      html.EscapeString(t.URL),
+			"vals":     html.EscapeString(string(vals)),
+			"include":  t.selector(".datatable-filters input[type=search]"),
+			"target":   "#" + t.ID,
+			"swap":     "outerHTML",
+			"push-url": "true",
+		}},
+		Href:      html.EscapeString(t.URL + "?" + t.Encode(next).Encode()),
+		InnerHTML: props.InnerHTML,
+	})
+	return elements.Th(props)
+}
+
+// filters renders the row of search inputs, carrying the sort as well
+func (t DataTable[T]) filters(q Query) string {
+	hx := utils.HtmxProps{
+		"get":      html.EscapeString(t.URL),
+		"trigger":  "input changed delay:300ms, search",
+		"include":  t.selector(".datatable-filters input"),
+		"target":   t.selector("tbody"),
+		"select":   t.selector("tbody"),
+		"swap":     "outerHTML",
+		"push-url": "true",
+	}
+	if t.Export != "" {
+		hx["select-oob"] = "#" + t.exportID()
+	}
+
+	var state string
+	if q.Sort != "" {
+		order := "asc"
+		if q.Desc {
+			order = "desc"
+		}
+		state = elements.InputHidden(elements.InputHiddenProps{Name: t.param("sort"), Value: html.EscapeString(q.Sort)}) +
+			elements.InputHidden(elements.InputHiddenProps{Name: t.param("order"), Value: order})
+	}
+
+	var cells strings.Builder
+	for _, c := range t.Columns {
+		var input string
+		if c.Filter {
+			input = elements.InputSearch(elements.InputSearchProps{
+				GlobalProps: elements.GlobalProps{
+					Aria: utils.AriaRoles{"label": html.EscapeString("Filter " + c.Header)},
+					Htmx: hx,
+				},
+				Name:  html.EscapeString(t.filterParam(c.Key)),
+				Value: html.EscapeString(q.Filters[c.Key]),
+			})
+		}
+		cells.WriteString(elements.Td(elements.TdProps{InnerHTML: state + input}))
+		state = ""
+	}
+
+	return elements.Tr(elements.TrProps{
+		GlobalProps: elements.GlobalProps{Class: []string{"datatable-filters"}},
+		InnerHTML:   cells.String(),
+	})
+}
+
+func (t DataTable[T]) selector(s string) string {
+	return "#" + t.ID + " " + s
+}
+
+func (t DataTable[T]) body(rows []T) string {
+	var b strings.Builder
+	for _, row := range rows {
+		var cells strings.Builder
+		for _, c := range t.Columns {
+			var cell string
+			if c.Cell != nil {
+				cell = c.Cell(row)
+			} else if c.Text != nil {
+				cell = html.EscapeString(c.Text(row))
+			}
+
+			if c.RowHeader {
+				cells.WriteString(elements.Th(elements.ThProps{Scope: elements.ThOptions.Scope.Row, InnerHTML: cell}))
+			} else {
+				cells.WriteString(elements.Td(elements.TdProps{InnerHTML: cell}))
+			}
+		}
+		b.WriteString(elements.Tr(elements.TrProps{InnerHTML: cells.String()}))
+	}
+
+	if len(rows) == 0 && t.Empty != "" {
+		span := len(t.Columns)
+		b.WriteString(elements.Tr(elements.TrProps{InnerHTML: elements.Td(elements.TdProps{
+			GlobalProps: elements.GlobalProps{Class: []string{"datatable-empty"}},
+			Colspan:     &span,
+			InnerHTML:   html.EscapeString(t.Empty),
+		})}))
+	}
+
+	return elements.Tbody(elements.TbodyProps{InnerHTML: b.String()})
+}
@@ -1,8 +1,25 @@
 package elements
 
+func init() {
+	ReferrerpolicyOptions = referrerpolicyOptions{
+		NoReferrer:          referrerpolicyOptionNoReferrer,
+		NoReferrerDowngrade: referrerpolicyOptionNoReferrerDowngrade,
+		Origin:              referrerpolicyOptionOrigin,
+		CrossOrigin:         referrerpolicyOptionCrossOrigin,
+		SameOrigin:          referrerpolicyOptionSameOrigin,
+		StrictOrigin:        referrerpolicyOptionStrictOrigin,
+		StrictCrossOrigin:   referrerpolicyOptionStrictCrossOrigin,
+		Unsafe:              referrerpolicyOptionUnsafe,
+	}
+}
+
 /* Referrerpolicy */
 type referrerpolicyOption struct{ string }
 
+// Named outside this package by document-level policies, such as the
+// Referrer-Policy response header, so they share the element values
+type ReferrerpolicyOption = referrerpolicyOption
+
 func (o referrerpolicyOption) String() string { return o.string }
 
 func referrerpolicyOptionNoReferrer() referrerpolicyOption {
@@ -30,7 +47,7 @@ func referrerpolicyOptionStrictOrigin() referrerpolicyOption {
 }
 
 func referrerpolicyOptionStrictCrossOrigin() referrerpolicyOption {
-	return referrerpolicyOption{"same-origin-when-cross-origin"}
+	return referrerpolicyOption{"strict-origin-when-cross-origin"}
 }
 
 func referrerpolicyOptionUnsafe() referrerpolicyOption {
@@ -48,6 +65,8 @@ type referrerpolicyOptions struct {
 	Unsafe              func() referrerpolicyOption
 }
 
+var ReferrerpolicyOptions referrerpolicyOptions
+
 /* Crossorigin */
 type crossoriginOption struct{ string }
 
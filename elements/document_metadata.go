@@ -6,6 +6,76 @@ import (
 	. "github.com/bitpartio/Mx/utils"
 )
 
+func init() {
+	MetaOptions = metaOptions{
+		HttpEquiv: metaHttpEquivOptions{
+			ContentSecurityPolicy: metaHttpEquivOptionContentSecurityPolicy,
+			ContentType:           metaHttpEquivOptionContentType,
+			DefaultStyle:          metaHttpEquivOptionDefaultStyle,
+			Refresh:               metaHttpEquivOptionRefresh,
+			XUACompatible:         metaHttpEquivOptionXUACompatible,
+		},
+	}
+
+	LinkOptions = linkOptions{
+		As: linkAsOptions{
+			Audio:    linkAsOptionAudio,
+			Document: linkAsOptionDocument,
+			Embed:    linkAsOptionEmbed,
+			Fetch:    linkAsOptionFetch,
+			Font:     linkAsOptionFont,
+			Image:    linkAsOptionImage,
+			Object:   linkAsOptionObject,
+			Script:   linkAsOptionScript,
+			Style:    linkAsOptionStyle,
+			Track:    linkAsOptionTrack,
+			Video:    linkAsOptionVideo,
+			Worker:   linkAsOptionWorker,
+		},
+		Blocking: blockingOptions{
+			Render: blockingOptionRender,
+		},
+		Crossorigin: crossoriginOptions{
+			Anonymous:   crossoriginOptionAnonymous,
+			Credentials: crossoriginOptionCredentials,
+		},
+		Fetchpriority: fetchpriorityOptions{
+			High: fetchpriorityOptionHigh,
+			Low:  fetchpriorityOptionLow,
+			Auto: fetchpriorityOptionAuto,
+		},
+		Referrerpolicy: referrerpolicyOptions{
+			NoReferrer:          referrerpolicyOptionNoReferrer,
+			NoReferrerDowngrade: referrerpolicyOptionNoReferrerDowngrade,
+			Origin:              referrerpolicyOptionOrigin,
+			CrossOrigin:         referrerpolicyOptionCrossOrigin,
+			SameOrigin:          referrerpolicyOptionSameOrigin,
+			StrictOrigin:        referrerpolicyOptionStrictOrigin,
+			StrictCrossOrigin:   referrerpolicyOptionStrictCrossOrigin,
+			Unsafe:              referrerpolicyOptionUnsafe,
+		},
+		Rel: linkRelOptions{
+			Alternate:      linkRelOptionAlternate,
+			AppleTouchIcon: linkRelOptionAppleTouchIcon,
+			Author:         linkRelOptionAuthor,
+			Canonical:      linkRelOptionCanonical,
+			DNSPrefetch:    linkRelOptionDNSPrefetch,
+			Help:           linkRelOptionHelp,
+			Icon:           linkRelOptionIcon,
+			License:        linkRelOptionLicense,
+			Manifest:       linkRelOptionManifest,
+			Modulepreload:  linkRelOptionModulepreload,
+			Next:           linkRelOptionNext,
+			Preconnect:     linkRelOptionPreconnect,
+			Prefetch:       linkRelOptionPrefetch,
+			Preload:        linkRelOptionPreload,
+			Prev:           linkRelOptionPrev,
+			Search:         linkRelOptionSearch,
+			Stylesheet:     linkRelOptionStylesheet,
+		},
+	}
+}
+
 /*
  * Specifies the base URL to use for all relative URLs in a document. There
  * can be only one such element in a document.
@@ -66,11 +136,76 @@ func Head(props HeadProps) string {
  */
 type LinkProps struct {
 	GlobalProps
+
+	As             func() linkAsOption
+	Blocking       []func() blockingOption
+	Crossorigin    func() crossoriginOption
+	Fetchpriority  func() fetchpriorityOption
+	Href           string
+	Hreflang       string // Limited values but too complex for enum. Ref: https://datatracker.ietf.org/doc/html/rfc5646
+	Imagesizes     []string
+	Imagesrcset    []string
+	Integrity      string
+	Media          string
+	Referrerpolicy func() referrerpolicyOption
+	Rel            []func() linkRelOption
+	Sizes          []string // "any" or WIDTHxHEIGHT, e.g. "32x32"
+	Type           string   // Limited values but too complex for enum. Ref: https://www.iana.org/assignments/media-types/media-types.xhtml
 }
 
 func Link(props LinkProps) string {
+	var as string
+	if props.As != nil {
+		as = BuildProp("as", props.As().String())
+	}
+	var blocking string
+	if len(props.Blocking) > 0 {
+		blockingStrings := make([]string, len(props.Blocking))
+		for k, b := range props.Blocking {
+			blockingStrings[k] = b().String()
+		}
+
+		blocking = BuildPropListWithSpaces("blocking", blockingStrings)
+	}
+	var crossorigin string
+	if props.Crossorigin != nil {
+		crossorigin = BuildProp("crossorigin", props.Crossorigin().String())
+	}
+	var fetchpriority string
+	if props.Fetchpriority != nil {
+		fetchpriority = BuildProp("fetchpriority", props.Fetchpriority().String())
+	}
+	var referrerpolicy string
+	if props.Referrerpolicy != nil {
+		referrerpolicy = BuildProp("referrerpolicy", props.Referrerpolicy().String())
+	}
+	var rel string
+	if len(props.Rel) > 0 {
+		relStrings := make([]string, len(props.Rel))
+		for k, rel := range props.Rel {
+			relStrings[k] = rel().String()
+		}
+
+		rel = BuildPropListWithSpaces("rel", relStrings)
+	}
+
 	values := map[string]interface{}{
 		"global": BuildGlobalProps(props.GlobalProps),
+
+		"as":             as,
+		"blocking":       blocking,
+		"crossorigin":    crossorigin,
+		"fetchpriority":  fetchpriority,
+		"href":           BuildProp("href", props.Href),
+		"hreflang":       BuildProp("hreflang", props.Hreflang),
+		"imagesizes":     BuildPropListWithCommas("imagesizes", props.Imagesizes),
+		"imagesrcset":    BuildPropListWithCommas("imagesrcset", props.Imagesrcset),
+		"integrity":      BuildProp("integrity", props.Integrity),
+		"media":          BuildProp("media", props.Media),
+		"referrerpolicy": referrerpolicy,
+		"rel":            rel,
+		"sizes":          BuildPropListWithSpaces("sizes", props.Sizes),
+		"type":           BuildProp("type", props.Type),
 	}
 
 	m := BuildMarkup("link", values)
@@ -81,20 +216,260 @@ func Link(props LinkProps) string {
 	return s
 }
 
+// <link rel="stylesheet" href="...">
+func Stylesheet(href string) string {
+	return Link(LinkProps{
+		Rel:  []func() linkRelOption{linkRelOptionStylesheet},
+		Href: href,
+	})
+}
+
+/*
+ * <link rel="preload" href="..." as="...">, leaving out as when nil. Fonts
+ * are always fetched in CORS mode, so preloading one adds crossorigin or
+ * the browser would download it twice.
+ */
+func Preload(href string, as func() linkAsOption) string {
+	props := LinkProps{
+		Rel:  []func() linkRelOption{linkRelOptionPreload},
+		Href: href,
+		As:   as,
+	}
+	if as != nil && as().String() == "font" {
+		props.Crossorigin = crossoriginOptionAnonymous
+	}
+	return Link(props)
+}
+
+// <link rel="icon" href="..." sizes="...">
+func Icon(href string, sizes ...string) string {
+	return Link(LinkProps{
+		Rel:   []func() linkRelOption{linkRelOptionIcon},
+		Href:  href,
+		Sizes: sizes,
+	})
+}
+
+// <link rel="preconnect" href="..."> to open a connection to an origin early
+func Preconnect(origin string) string {
+	return Link(LinkProps{
+		Rel:  []func() linkRelOption{linkRelOptionPreconnect},
+		Href: origin,
+	})
+}
+
+// <link rel="canonical" href="...">
+func Canonical(href string) string {
+	return Link(LinkProps{
+		Rel:  []func() linkRelOption{linkRelOptionCanonical},
+		Href: href,
+	})
+}
+
+type linkOptions struct {
+	As             linkAsOptions
+	Blocking       blockingOptions
+	Crossorigin    crossoriginOptions
+	Fetchpriority  fetchpriorityOptions
+	Referrerpolicy referrerpolicyOptions
+	Rel            linkRelOptions
+}
+
+var LinkOptions linkOptions
+
+/* As */
+type linkAsOption struct{ string }
+
+func (o linkAsOption) String() string { return o.string }
+
+func linkAsOptionAudio() linkAsOption {
+	return linkAsOption{"audio"}
+}
+
+func linkAsOptionDocument() linkAsOption {
+	return linkAsOption{"document"}
+}
+
+func linkAsOptionEmbed() linkAsOption {
+	return linkAsOption{"embed"}
+}
+
+func linkAsOptionFetch() linkAsOption {
+	return linkAsOption{"fetch"}
+}
+
+func linkAsOptionFont() linkAsOption {
+	return linkAsOption{"font"}
+}
+
+func linkAsOptionImage() linkAsOption {
+	return linkAsOption{"image"}
+}
+
+func linkAsOptionObject() linkAsOption {
+	return linkAsOption{"object"}
+}
+
+func linkAsOptionScript() linkAsOption {
+	return linkAsOption{"script"}
+}
+
+func linkAsOptionStyle() linkAsOption {
+	return linkAsOption{"style"}
+}
+
+func linkAsOptionTrack() linkAsOption {
+	return linkAsOption{"track"}
+}
+
+func linkAsOptionVideo() linkAsOption {
+	return linkAsOption{"video"}
+}
+
+func linkAsOptionWorker() linkAsOption {
+	return linkAsOption{"worker"}
+}
+
+type linkAsOptions struct {
+	Audio    func() linkAsOption
+	Document func() linkAsOption
+	Embed    func() linkAsOption
+	Fetch    func() linkAsOption
+	Font     func() linkAsOption
+	Image    func() linkAsOption
+	Object   func() linkAsOption
+	Script   func() linkAsOption
+	Style    func() linkAsOption
+	Track    func() linkAsOption
+	Video    func() linkAsOption
+	Worker   func() linkAsOption
+}
+
+/* Rel */
+type linkRelOption struct{ string }
+
+func (o linkRelOption) String() string { return o.string }
+
+func linkRelOptionAlternate() linkRelOption {
+	return linkRelOption{"alternate"}
+}
+
+func linkRelOptionAppleTouchIcon() linkRelOption {
+	return linkRelOption{"apple-touch-icon"}
+}
+
+func linkRelOptionAuthor() linkRelOption {
+	return linkRelOption{"author"}
+}
+
+func linkRelOptionCanonical() linkRelOption {
+	return linkRelOption{"canonical"}
+}
+
+func linkRelOptionDNSPrefetch() linkRelOption {
+	return linkRelOption{"dns-prefetch"}
+}
+
+func linkRelOptionHelp() linkRelOption {
+	return linkRelOption{"help"}
+}
+
+func linkRelOptionIcon() linkRelOption {
+	return linkRelOption{"icon"}
+}
+
+func linkRelOptionLicense() linkRelOption {
+	return linkRelOption{"license"}
+}
+
+func linkRelOptionManifest() linkRelOption {
+	return linkRelOption{"manifest"}
+}
+
+func linkRelOptionModulepreload() linkRelOption {
+	return linkRelOption{"modulepreload"}
+}
+
+func linkRelOptionNext() linkRelOption {
+	return linkRelOption{"next"}
+}
+
+func linkRelOptionPreconnect() linkRelOption {
+	return linkRelOption{"preconnect"}
+}
+
+func linkRelOptionPrefetch() linkRelOption {
+	return linkRelOption{"prefetch"}
+}
+
+func linkRelOptionPreload() linkRelOption {
+	return linkRelOption{"preload"}
+}
+
+func linkRelOptionPrev() linkRelOption {
+	return linkRelOption{"prev"}
+}
+
+func linkRelOptionSearch() linkRelOption {
+	return linkRelOption{"search"}
+}
+
+func linkRelOptionStylesheet() linkRelOption {
+	return linkRelOption{"stylesheet"}
+}
+
+type linkRelOptions struct {
+	Alternate      func() linkRelOption
+	AppleTouchIcon func() linkRelOption
+	Author         func() linkRelOption
+	Canonical      func() linkRelOption
+	DNSPrefetch    func() linkRelOption
+	Help           func() linkRelOption
+	Icon           func() linkRelOption
+	License        func() linkRelOption
+	Manifest       func() linkRelOption
+	Modulepreload  func() linkRelOption
+	Next           func() linkRelOption
+	Preconnect     func() linkRelOption
+	Prefetch       func() linkRelOption
+	Preload        func() linkRelOption
+	Prev           func() linkRelOption
+	Search         func() linkRelOption
+	Stylesheet     func() linkRelOption
+}
+
+type LinkRelOptions []func() linkRelOption
+
 /*
  * Represents metadata that cannot be represented by other HTML meta-related
  * elements, like <base>, <link>, <script>, <style> and <title>.
  */
 type MetaProps struct {
 	GlobalProps
-	Charset string
+
+	Charset   string
+	Content   string
+	HttpEquiv func() metaHttpEquivOption
+	Media     string
+	Name      string // Standard and extension names too many for enum, e.g. "description" or "twitter:card"
+	Property  string // RDFa, used by OpenGraph, e.g. "og:title"
 }
 
 func Meta(props MetaProps) string {
+	var httpEquiv string
+	if props.HttpEquiv != nil {
+		httpEquiv = BuildProp("http-equiv", props.HttpEquiv().String())
+	}
+
 	values := map[string]interface{}{
 		"global": BuildGlobalProps(props.GlobalProps),
 
-		"charset": BuildProp("charset", props.Charset),
+		"charset":    BuildProp("charset", props.Charset),
+		"content":    BuildProp("content", props.Content),
+		"http-equiv": httpEquiv,
+		"media":      BuildProp("media", props.Media),
+		"name":       BuildProp("name", props.Name),
+		"property":   BuildProp("property", props.Property),
 	}
 
 	m := BuildMarkup("meta", values)
@@ -105,6 +480,45 @@ func Meta(props MetaProps) string {
 	return s
 }
 
+type metaOptions struct {
+	HttpEquiv metaHttpEquivOptions
+}
+
+var MetaOptions metaOptions
+
+/* HttpEquiv */
+type metaHttpEquivOption struct{ string }
+
+func (o metaHttpEquivOption) String() string { return o.string }
+
+func metaHttpEquivOptionContentSecurityPolicy() metaHttpEquivOption {
+	return metaHttpEquivOption{"content-security-policy"}
+}
+
+func metaHttpEquivOptionContentType() metaHttpEquivOption {
+	return metaHttpEquivOption{"content-type"}
+}
+
+func metaHttpEquivOptionDefaultStyle() metaHttpEquivOption {
+	return metaHttpEquivOption{"default-style"}
+}
+
+func metaHttpEquivOptionRefresh() metaHttpEquivOption {
+	return metaHttpEquivOption{"refresh"}
+}
+
+func metaHttpEquivOptionXUACompatible() metaHttpEquivOption {
+	return metaHttpEquivOption{"x-ua-compatible"}
+}
+
+type metaHttpEquivOptions struct {
+	ContentSecurityPolicy func() metaHttpEquivOption
+	ContentType           func() metaHttpEquivOption
+	DefaultStyle          func() metaHttpEquivOption
+	Refresh               func() metaHttpEquivOption
+	XUACompatible         func() metaHttpEquivOption
+}
+
 /*
  * Contains style information for a document, or part of a document. It
  * contains CSS, which is applied to the contents of the document containing
@@ -0,0 +1,63 @@
+package elements
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScriptText(t *testing.T) {
+	for _, c := range []struct {
+		in, want string
+	}{
+		{`alert(1)`, `alert(1)`},
+		{`s = "</script><img onerror=x>"`, `s = "\x3C/script><img onerror=x>"`},
+		{`s = '</SCRIPT '`, `s = '\x3C/SCRIPT '`},
+		{"s = `<!-- <script>`", "s = `\\x3C!-- <script>`"},
+		// \x3C is a valid escape in regular expressions, u flag included
+		{`/<!--/u.test(s)`, `/\x3C!--/u.test(s)`},
+		{`/<\/script/.test(s)`, `/<\/script/.test(s)`},
+		{"s = \"a\u2028b\u2029c\"", `s = "a\u2028b\u2029c"`},
+	} {
+		if got := ScriptText(c.in); got != c.want {
+			t.Errorf("ScriptText(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestScriptJSON(t *testing.T) {
+	for _, c := range []struct {
+		in   interface{}
+		want string
+	}{
+		{"</script>", `"\u003c/script\u003e"`},
+		{"<!--", `"\u003c!--"`},
+		{"a\u2028b\u2029c", `"a\u2028b\u2029c"`},
+		{map[string]string{"q": "a&b"}, `{"q":"a\u0026b"}`},
+	} {
+		got, err := ScriptJSON(c.in)
+		if err != nil || got != c.want {
+			t.Errorf("ScriptJSON(%q) = %q, %v, want %q", c.in, got, err, c.want)
+		}
+	}
+
+	if _, err := ScriptJSON(func() {}); err == nil {
+		t.Error("marshalled a function")
+	}
+}
+
+func TestImportMap(t *testing.T) {
+	got := ImportMap(ImportMapProps{
+		Imports: map[string]string{"app": "/js/app.js?</script><!--\u2028"},
+	})
+	for _, want := range []string{
+		`type="importmap"`,
+		`{"imports":{"app":"/js/app.js?\u003c/script\u003e\u003c!--\u2028"}}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	if strings.Count(strings.ToLower(got), "</script") != 1 || strings.Contains(got, "<!--") {
+		t.Errorf("unescaped: %s", got)
+	}
+}
@@ -410,6 +410,9 @@ var FormOptions formOptions
 /* Autocomplete */
 type autocompleteFormOption struct{ string }
 
+// Named outside this package, see AutocompleteInputOption
+type AutocompleteFormOption = autocompleteFormOption
+
 func (o autocompleteFormOption) String() string { return o.string }
 
 func autocompleteFormOptionOff() autocompleteFormOption {
@@ -574,6 +577,10 @@ func InputButton(props InputButtonProps) string {
 /* Autocomplete */
 type autocompleteInputOption struct{ string }
 
+// Named outside this package by code choosing a token at runtime, such as
+// a form generated from struct tags
+type AutocompleteInputOption = autocompleteInputOption
+
 func (o autocompleteInputOption) String() string { return o.string }
 
 func autocompleteInputOptionOff() autocompleteInputOption {
@@ -804,6 +811,7 @@ type InputCheckboxProps struct {
 	Disabled bool
 	Form     string
 	Name     string
+	Required bool
 	Value    string
 }
 
@@ -817,6 +825,7 @@ func InputCheckbox(props InputCheckboxProps) string {
 		"disabled": BuildBooleanProp("disabled", props.Disabled),
 		"form":     BuildProp("form", props.Form),
 		"name":     BuildProp("name", props.Name),
+		"required": BuildBooleanProp("required", props.Required),
 		"value":    BuildProp("value", props.Value),
 	}
 
@@ -948,8 +957,8 @@ func InputDatetimeLocal(props InputDatetimeLocalProps) string {
 		"disabled":     BuildBooleanProp("disabled", props.Disabled),
 		"form":         BuildProp("form", props.Form),
 		"list":         BuildProp("list", props.List),
-		"max":          BuildDateTimeProp("max", props.Max),
-		"min":          BuildDateTimeProp("max", props.Min),
+		"max":          BuildDateTimeLocalProp("max", props.Max),
+		"min":          BuildDateTimeLocalProp("min", props.Min),
 		"name":         BuildProp("name", props.Name),
 		"readonly":     BuildBooleanProp("readonly", props.Readonly),
 		"required":     BuildBooleanProp("required", props.Required),
@@ -1117,8 +1126,8 @@ func InputHidden(props InputHiddenProps) string {
 		"autocomplete": autocomplete,
 		"disabled":     BuildBooleanProp("disabled", props.Disabled),
 		"form":         BuildProp("form", props.Form),
-		"name":         BuildProp("name", props.Form),
-		"value":        BuildProp("value", props.Form),
+		"name":         BuildProp("name", props.Name),
+		"value":        BuildProp("value", props.Value),
 	}
 
 	m := BuildMarkup("input", values)
@@ -1231,7 +1240,7 @@ func InputMonth(props InputMonthProps) string {
 		"form":         BuildProp("form", props.Form),
 		"list":         BuildProp("list", props.List),
 		"max":          BuildDateMonthProp("max", props.Max),
-		"min":          BuildDateMonthProp("max", props.Min),
+		"min":          BuildDateMonthProp("min", props.Min),
 		"name":         BuildProp("name", props.Name),
 		"readonly":     BuildBooleanProp("readonly", props.Readonly),
 		"required":     BuildBooleanProp("required", props.Required),
@@ -1335,6 +1344,8 @@ func InputPassword(props InputPasswordProps) string {
 		"name":         BuildProp("name", props.Name),
 		"pattern":      BuildProp("pattern", props.Pattern),
 		"placeholder":  BuildProp("placeholder", props.Placeholder),
+		"readonly":     BuildBooleanProp("readonly", props.Readonly),
+		"required":     BuildBooleanProp("required", props.Required),
 		"size":         BuildIntProp("size", props.Size),
 		"value":        BuildProp("value", props.Value),
 	}
@@ -1893,11 +1904,11 @@ func Meter(props MeterProps) string {
 	values := map[string]interface{}{
 		"global": BuildGlobalProps(props.GlobalProps),
 
-		"high":    BuildFloatProp("high", props.Max),
-		"low":     BuildFloatProp("low", props.Min),
+		"high":    BuildFloatProp("high", props.High),
+		"low":     BuildFloatProp("low", props.Low),
 		"max":     BuildFloatProp("max", props.Max),
 		"min":     BuildFloatProp("min", props.Min),
-		"optimum": BuildFloatProp("optimum", props.Min),
+		"optimum": BuildFloatProp("optimum", props.Optimum),
 		"value":   BuildProp("value", props.Value),
 
 		"innerhtml": props.InnerHTML,
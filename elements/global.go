@@ -34,10 +34,10 @@ type GlobalProps struct {
 	Inputmode             string
 	Is                    string
 	Itemid                string
-	Itemprop              string
-	Itemref               string
-	Itemscope             string
-	Itemtype              string
+	Itemprop              []string
+	Itemref               []string // IDs of elements elsewhere in the document holding properties of this item
+	Itemscope             bool
+	Itemtype              []string // Absolute vocabulary URLs, e.g. "https://schema.org/Product"
 	Lang                  string
 	Nonce                 string
 	Part                  string
@@ -138,7 +138,8 @@ func init() {
 		},
 		Dir: dirOptions{
 			Ltr:  dirOptionLtr,
-			Rtr:  dirOptionRtr,
+			Rtl:  dirOptionRtl,
+			Rtr:  dirOptionRtl,
 			Auto: dirOptionAuto,
 		},
 		Hidden: hiddenOptions{
@@ -194,14 +195,18 @@ type autocapitalizeOptions struct {
 
 type dirOption struct{ string }
 
+// Named outside this package by code choosing the direction at runtime,
+// such as from a locale
+type DirOption = dirOption
+
 func (o dirOption) String() string { return o.string }
 
 func dirOptionLtr() dirOption {
 	return dirOption{"ltr"}
 }
 
-func dirOptionRtr() dirOption {
-	return dirOption{"rtr"}
+func dirOptionRtl() dirOption {
+	return dirOption{"rtl"}
 }
 
 func dirOptionAuto() dirOption {
@@ -209,7 +214,9 @@ func dirOptionAuto() dirOption {
 }
 
 type dirOptions struct {
-	Ltr  func() dirOption
+	Ltr func() dirOption
+	Rtl func() dirOption
+	// Deprecated: misspelling of Rtl, kept for existing callers
 	Rtr  func() dirOption
 	Auto func() dirOption
 }
@@ -261,11 +268,24 @@ func buildGlobalProps(props GlobalProps) map[string]interface{} {
 		values["dir"] = BuildProp("dir", props.Dir().String())
 	}
 
+	// lang
+	values["lang"] = BuildProp("lang", props.Lang)
+
 	// hidden
 	if props.Hidden != nil {
 		values["hidden"] = BuildProp("hidden", props.Hidden().String())
 	}
 
+	// nonce
+	values["nonce"] = BuildProp("nonce", props.Nonce)
+
+	// microdata
+	values["itemid"] = BuildProp("itemid", props.Itemid)
+	values["itemprop"] = BuildPropListWithSpaces("itemprop", props.Itemprop)
+	values["itemref"] = BuildPropListWithSpaces("itemref", props.Itemref)
+	values["itemscope"] = BuildBooleanProp("itemscope", props.Itemscope)
+	values["itemtype"] = BuildPropListWithSpaces("itemtype", props.Itemtype)
+
 	values["data"] = BuildDataValues(props.Data)
 	values["aria"] = BuildAriaRoles(props.Aria)
 	values["htmx"] = BuildHtmxProps(props.Htmx)
@@ -284,7 +304,14 @@ func BuildGlobalProps(props GlobalProps) string {
 		"{{class}}",
 		"{{autocapitalize}}",
 		"{{dir}}",
+		"{{lang}}",
 		"{{hidden}}",
+		"{{nonce}}",
+		"{{itemscope}}",
+		"{{itemtype}}",
+		"{{itemid}}",
+		"{{itemprop}}",
+		"{{itemref}}",
 		"{{data}}",
 		"{{aria}}",
 		"{{htmx}}",
@@ -3,6 +3,8 @@ package elements
 // Ref: https://developer.mozilla.org/en-US/docs/Web/HTML/Element#inline_text_semantics
 
 import (
+	"time"
+
 	. "github.com/bitpartio/Mx/utils"
 )
 
@@ -105,6 +107,10 @@ var AOptions aOptions
 /* Rel */
 type aRelOption struct{ string }
 
+// Named outside this package by components building links, such as
+// pagination's rel="next" and rel="prev"
+type ARelOption = aRelOption
+
 func (o aRelOption) String() string { return o.string }
 
 func aRelOptionAlternate() aRelOption {
@@ -810,6 +816,8 @@ func Sup(props SupProps) string {
 type TimeProps struct {
 	GlobalProps
 
+	Datetime time.Time
+
 	InnerHTML string
 }
 
@@ -817,6 +825,8 @@ func Time(props TimeProps) string {
 	values := map[string]interface{}{
 		"global": BuildGlobalProps(props.GlobalProps),
 
+		"datetime": BuildDateTimeProp("datetime", props.Datetime),
+
 		"innerhtml": props.InnerHTML,
 	}
 
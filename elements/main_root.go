@@ -19,10 +19,17 @@ type HTMLProps struct {
 }
 
 func HTML(props HTMLProps) string {
+	// Either Lang will do, rendered once
+	lang := props.Lang
+	if lang == "" {
+		lang = props.GlobalProps.Lang
+	}
+	props.GlobalProps.Lang = ""
+
 	values := map[string]interface{}{
 		"global": BuildGlobalProps(props.GlobalProps),
 
-		"lang":  BuildProp("lang", props.Lang),
+		"lang":  BuildProp("lang", lang),
 		"xmlns": BuildProp("xmlns", props.Xmlns),
 
 		"innerhtml": props.InnerHTML,
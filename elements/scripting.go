@@ -2,7 +2,47 @@ package elements
 
 // Ref: https://developer.mozilla.org/en-US/docs/Web/HTML/Element#scripting
 
-import . "github.com/bitpartio/Mx/utils"
+import (
+	"encoding/json"
+	"regexp"
+
+	. "github.com/bitpartio/Mx/utils"
+)
+
+func init() {
+	ScriptOptions = scriptOptions{
+		Blocking: blockingOptions{
+			Render: blockingOptionRender,
+		},
+		Crossorigin: crossoriginOptions{
+			Anonymous:   crossoriginOptionAnonymous,
+			Credentials: crossoriginOptionCredentials,
+		},
+		Fetchpriority: fetchpriorityOptions{
+			High: fetchpriorityOptionHigh,
+			Low:  fetchpriorityOptionLow,
+			Auto: fetchpriorityOptionAuto,
+		},
+		Referrerpolicy: referrerpolicyOptions{
+			NoReferrer:          referrerpolicyOptionNoReferrer,
+			NoReferrerDowngrade: referrerpolicyOptionNoReferrerDowngrade,
+			Origin:              referrerpolicyOptionOrigin,
+			CrossOrigin:         referrerpolicyOptionCrossOrigin,
+			SameOrigin:          referrerpolicyOptionSameOrigin,
+			StrictOrigin:        referrerpolicyOptionStrictOrigin,
+			StrictCrossOrigin:   referrerpolicyOptionStrictCrossOrigin,
+			Unsafe:              referrerpolicyOptionUnsafe,
+		},
+		Type: scriptTypeOptions{
+			Classic:          scriptTypeOptionClassic,
+			Module:           scriptTypeOptionModule,
+			Importmap:        scriptTypeOptionImportmap,
+			Speculationrules: scriptTypeOptionSpeculationrules,
+			JSON:             scriptTypeOptionJSON,
+			JSONLD:           scriptTypeOptionJSONLD,
+		},
+	}
+}
 
 /*
  * Container element to use with either the canvas scripting API or the
@@ -65,17 +105,68 @@ func Noscript(props NoscriptProps) string {
  * or refer to JavaScript code. The <script> element can also be used with
  * other languages, such as WebGL's GLSL shader programming language and
  * JSON.
+ *
+ * InnerHTML is written as-is; wrap inline code with ScriptText or ScriptJSON
+ * so it cannot close the element early.
  */
 type ScriptProps struct {
 	GlobalProps
 
+	Async          bool
+	Blocking       []func() blockingOption
+	Crossorigin    func() crossoriginOption
+	Defer          bool
+	Fetchpriority  func() fetchpriorityOption
+	Integrity      string
+	Nomodule       bool
+	Referrerpolicy func() referrerpolicyOption
+	Src            string
+	Type           func() scriptTypeOption
+
 	InnerHTML string
 }
 
 func Script(props ScriptProps) string {
+	var blocking string
+	if len(props.Blocking) > 0 {
+		blockingStrings := make([]string, len(props.Blocking))
+		for k, b := range props.Blocking {
+			blockingStrings[k] = b().String()
+		}
+
+		blocking = BuildPropListWithSpaces("blocking", blockingStrings)
+	}
+	var crossorigin string
+	if props.Crossorigin != nil {
+		crossorigin = BuildProp("crossorigin", props.Crossorigin().String())
+	}
+	var fetchpriority string
+	if props.Fetchpriority != nil {
+		fetchpriority = BuildProp("fetchpriority", props.Fetchpriority().String())
+	}
+	var referrerpolicy string
+	if props.Referrerpolicy != nil {
+		referrerpolicy = BuildProp("referrerpolicy", props.Referrerpolicy().String())
+	}
+	var typeOf string
+	if props.Type != nil {
+		typeOf = BuildProp("type", props.Type().String())
+	}
+
 	values := map[string]interface{}{
 		"global": BuildGlobalProps(props.GlobalProps),
 
+		"async":          BuildBooleanProp("async", props.Async),
+		"blocking":       blocking,
+		"crossorigin":    crossorigin,
+		"defer":          BuildBooleanProp("defer", props.Defer),
+		"fetchpriority":  fetchpriority,
+		"integrity":      BuildProp("integrity", props.Integrity),
+		"nomodule":       BuildBooleanProp("nomodule", props.Nomodule),
+		"referrerpolicy": referrerpolicy,
+		"src":            BuildProp("src", props.Src),
+		"type":           typeOf,
+
 		"innerhtml": props.InnerHTML,
 	}
 
@@ -86,3 +177,136 @@ func Script(props ScriptProps) string {
 	s := Render(t, values)
 	return s
 }
+
+type scriptOptions struct {
+	Blocking       blockingOptions
+	Crossorigin    crossoriginOptions
+	Fetchpriority  fetchpriorityOptions
+	Referrerpolicy referrerpolicyOptions
+	Type           scriptTypeOptions
+}
+
+var ScriptOptions scriptOptions
+
+/* Blocking */
+type blockingOption struct{ string }
+
+func (o blockingOption) String() string { return o.string }
+
+func blockingOptionRender() blockingOption {
+	return blockingOption{"render"}
+}
+
+type blockingOptions struct {
+	Render func() blockingOption
+}
+
+/* Type */
+type scriptTypeOption struct{ string }
+
+func (o scriptTypeOption) String() string { return o.string }
+
+func scriptTypeOptionClassic() scriptTypeOption {
+	return scriptTypeOption{"text/javascript"}
+}
+
+func scriptTypeOptionModule() scriptTypeOption {
+	return scriptTypeOption{"module"}
+}
+
+func scriptTypeOptionImportmap() scriptTypeOption {
+	return scriptTypeOption{"importmap"}
+}
+
+func scriptTypeOptionSpeculationrules() scriptTypeOption {
+	return scriptTypeOption{"speculationrules"}
+}
+
+func scriptTypeOptionJSON() scriptTypeOption {
+	return scriptTypeOption{"application/json"}
+}
+
+func scriptTypeOptionJSONLD() scriptTypeOption {
+	return scriptTypeOption{"application/ld+json"}
+}
+
+type scriptTypeOptions struct {
+	Classic          func() scriptTypeOption
+	Module           func() scriptTypeOption
+	Importmap        func() scriptTypeOption
+	Speculationrules func() scriptTypeOption
+	JSON             func() scriptTypeOption
+	JSONLD           func() scriptTypeOption
+}
+
+// Sequences that end a script element early or switch the parser into its
+// escaped state, and the line separators older engines reject inside
+// string literals. Ref: https://html.spec.whatwg.org/multipage/scripting.html#restrictions-for-contents-of-script-elements
+var scriptUnsafe = regexp.MustCompile("(?i)</script|<!--|\u2028|\u2029")
+
+/*
+ * Escapes inline JavaScript for use as a <script> element's InnerHTML.
+ * The "<" of "</script" and "<!--" is written as \x3C, and U+2028 and
+ * U+2029 as \u2028 and \u2029. Those escapes mean the same character in
+ * string, template and regular expression literals, with or without the
+ * u flag, so the sequences may only appear inside such literals; outside
+ * them no escape keeps the code valid. Marshal data with ScriptJSON
+ * instead.
+ */
+func ScriptText(js string) string {
+	return scriptUnsafe.ReplaceAllStringFunc(js, func(m string) string {
+		switch m {
+		case "\u2028":
+			return `\u2028`
+		case "\u2029":
+			return `\u2029`
+		}
+		return `\x3C` + m[1:]
+	})
+}
+
+/*
+ * Marshals v as JSON for use as a <script> element's InnerHTML. "<", ">",
+ * "&", U+2028 and U+2029 are written as unicode escapes, so the data cannot
+ * end the element no matter what strings it holds.
+ */
+func ScriptJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+/*
+ * Controls how browsers resolve bare module specifiers (import "htmx")
+ * to URLs for <script type="module">.
+ * Ref: https://developer.mozilla.org/en-US/docs/Web/HTML/Element/script/type/importmap
+ */
+type ImportMapProps struct {
+	GlobalProps
+
+	// Specifier to URL, e.g. "htmx": "/js/htmx.min.js"
+	Imports map[string]string
+	// URL prefix to specifier remappings used only within that scope
+	Scopes map[string]map[string]string
+	// Module URL to integrity metadata
+	Integrity map[string]string
+}
+
+func ImportMap(props ImportMapProps) string {
+	data := struct {
+		Imports   map[string]string            `json:"imports,omitempty"`
+		Scopes    map[string]map[string]string `json:"scopes,omitempty"`
+		Integrity map[string]string            `json:"integrity,omitempty"`
+	}{props.Imports, props.Scopes, props.Integrity}
+
+	// Maps of strings always marshal
+	inner, _ := ScriptJSON(data)
+
+	return Script(ScriptProps{
+		GlobalProps: props.GlobalProps,
+		Type:        scriptTypeOptionImportmap,
+		InnerHTML:   inner,
+	})
+}
@@ -4,6 +4,17 @@ package elements
 
 import . "github.com/bitpartio/Mx/utils"
 
+func init() {
+	ThOptions = thOptions{
+		Scope: thScopeOptions{
+			Row:      thScopeOptionRow,
+			Col:      thScopeOptionCol,
+			Rowgroup: thScopeOptionRowgroup,
+			Colgroup: thScopeOptionColgroup,
+		},
+	}
+}
+
 /*
  * Specifies the caption (or title) of a table.
  */
@@ -139,6 +150,11 @@ func Tbody(props TbodyProps) string {
 type TdProps struct {
 	GlobalProps
 
+	Colspan *int
+	// IDs of the <th> elements labelling the cell
+	Headers []string
+	Rowspan *int
+
 	InnerHTML string
 }
 
@@ -146,6 +162,10 @@ func Td(props TdProps) string {
 	values := map[string]interface{}{
 		"global": BuildGlobalProps(props.GlobalProps),
 
+		"colspan": BuildIntProp("colspan", props.Colspan),
+		"headers": BuildPropListWithSpaces("headers", props.Headers),
+		"rowspan": BuildIntProp("rowspan", props.Rowspan),
+
 		"innerhtml": props.InnerHTML,
 	}
 
@@ -187,13 +207,32 @@ func Tfoot(props TfootProps) string {
 type ThProps struct {
 	GlobalProps
 
+	// Short form of the header, read by screen readers
+	Abbr    string
+	Colspan *int
+	// IDs of the <th> elements labelling the cell
+	Headers []string
+	Rowspan *int
+	Scope   func() thScopeOption
+
 	InnerHTML string
 }
 
 func Th(props ThProps) string {
+	var scope string
+	if props.Scope != nil {
+		scope = BuildProp("scope", props.Scope().String())
+	}
+
 	values := map[string]interface{}{
 		"global": BuildGlobalProps(props.GlobalProps),
 
+		"abbr":    BuildProp("abbr", props.Abbr),
+		"colspan": BuildIntProp("colspan", props.Colspan),
+		"headers": BuildPropListWithSpaces("headers", props.Headers),
+		"rowspan": BuildIntProp("rowspan", props.Rowspan),
+		"scope":   scope,
+
 		"innerhtml": props.InnerHTML,
 	}
 
@@ -254,3 +293,37 @@ func Tr(props TrProps) string {
 	s := Render(t, values)
 	return s
 }
+
+type thOptions struct {
+	Scope thScopeOptions
+}
+
+var ThOptions thOptions
+
+/* Scope */
+type thScopeOption struct{ string }
+
+func (o thScopeOption) String() string { return o.string }
+
+func thScopeOptionRow() thScopeOption {
+	return thScopeOption{"row"}
+}
+
+func thScopeOptionCol() thScopeOption {
+	return thScopeOption{"col"}
+}
+
+func thScopeOptionRowgroup() thScopeOption {
+	return thScopeOption{"rowgroup"}
+}
+
+func thScopeOptionColgroup() thScopeOption {
+	return thScopeOption{"colgroup"}
+}
+
+type thScopeOptions struct {
+	Row      func() thScopeOption
+	Col      func() thScopeOption
+	Rowgroup func() thScopeOption
+	Colgroup func() thScopeOption
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/bitpartio/Mx/httpcache"
 	. "github.com/bitpartio/Mx/utils"
 )
 
@@ -16,10 +17,10 @@ func main() {
 	s := Minify(page)
 	// s := Clean(page)
 
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	http.Handle("/", httpcache.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		fmt.Fprintf(w, s)
-	})
+	}), httpcache.Options{Default: httpcache.Revalidate}))
 
 	http.HandleFunc("/basic", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -0,0 +1,259 @@
+package forms
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Largest part of a multipart form kept in memory while decoding
+const maxMemory = 32 << 20
+
+var errTarget = errors.New("forms: decode target must be a non-nil pointer to a struct")
+
+/*
+ * Decode reads the submitted form into v, a pointer to the tagged struct
+ * the form was rendered from, enforcing the constraints of its tags. Fields
+ * failing them keep their previous value and are reported in Errors, which
+ * Props.Errors takes to render the form again. The error is for requests
+ * that cannot be read at all.
+ *
+ *	var s Signup
+ *	errs, err := forms.Decode(r, &s, props)
+ *	if err != nil {
+ *		http.Error(w, err.Error(), http.StatusBadRequest)
+ *		return
+ *	}
+ *	if errs != nil {
+ *		props.Errors = errs
+ *		w.WriteHeader(http.StatusUnprocessableEntity)
+ *		fmt.Fprint(w, forms.Form(s, props))
+ *		return
+ *	}
+ */
+func Decode(r *http.Request, v interface{}, props Props) (Errors, error) {
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err = r.ParseMultipartForm(maxMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return DecodeValues(r.Form, v, props)
+}
+
+// DecodeValues is Decode for values already parsed
+func DecodeValues(form url.Values, v interface{}, props Props) (Errors, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, errTarget
+	}
+	rv = rv.Elem()
+	chooser, _ := v.(Chooser)
+
+	errs := Errors{}
+	for _, f := range fields(rv.Type()) {
+		values := make([]string, len(form[f.name]))
+		for i, s := range form[f.name] {
+			values[i] = sanitize(f.kind, s)
+		}
+
+		if err := f.check(values, props.choices(f, chooser)); err != nil {
+			errs[f.name] = err
+			continue
+		}
+		if !f.assign(f.settable(rv), values) {
+			errs[f.name] = newError(f, "badInput", "", values)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil, nil
+	}
+	return errs, nil
+}
+
+/*
+ * Validate checks v's current values against its tags, for data that did
+ * not arrive through Decode.
+ */
+func Validate(v interface{}, props Props) Errors {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	chooser, _ := v.(Chooser)
+
+	errs := Errors{}
+	for _, f := range fields(rv.Type()) {
+		var values []string
+		if fv, ok := f.value(rv); ok {
+			values = formatAll(f, fv)
+		}
+		if f.kind == "checkbox" && len(values) > 0 && values[0] == "false" {
+			values = nil
+		}
+		if err := f.check(values, props.choices(f, chooser)); err != nil {
+			errs[f.name] = err
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// settable finds the field, allocating nil pointers on the way
+func (f field) settable(v reflect.Value) reflect.Value {
+	for _, i := range f.index {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// assign stores checked values, reporting values the Go type cannot hold
+func (f field) assign(v reflect.Value, values []string) bool {
+	if f.multiple() {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+
+		if f.kind == "email" && len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+
+		s := reflect.MakeSlice(v.Type(), 0, len(values))
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if !f.assignOne(elem, value) {
+				return false
+			}
+			s = reflect.Append(s, elem)
+		}
+		v.Set(s)
+		return true
+	}
+
+	var value string
+	if len(values) > 0 {
+		value = values[0]
+	}
+	return f.assignOne(v, value)
+}
+
+func (f field) assignOne(v reflect.Value, s string) bool {
+	if v.Kind() == reflect.Pointer {
+		if s == "" && f.kind != "checkbox" {
+			v.Set(reflect.Zero(v.Type()))
+			return true
+		}
+		p := reflect.New(v.Type().Elem())
+		if !f.assignOne(p.Elem(), s) {
+			return false
+		}
+		v.Set(p)
+		return true
+	}
+
+	if v.Type() == timeType {
+		if s == "" {
+			v.Set(reflect.Zero(timeType))
+			return true
+		}
+		t, err := parseTime(f.kind, s)
+		if err != nil {
+			return false
+		}
+		v.Set(reflect.ValueOf(t))
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		// A checkbox is only submitted when checked
+		v.SetBool(s != "" && s != "false")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := parseInt(s)
+		if !ok || v.OverflowInt(n) {
+			return false
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := parseUint(s)
+		if !ok || v.OverflowUint(n) {
+			return false
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, ok := number(s)
+		if !ok || v.OverflowFloat(n) {
+			return false
+		}
+		v.SetFloat(n)
+	default:
+		return false
+	}
+	return true
+}
+
+// Largest integer a float64 holds exactly
+const maxExact = 1 << 53
+
+/*
+ * parseInt parses a whole number, 0 when empty. Decimal digits are parsed
+ * as an integer, keeping precision beyond 2^53; other valid floating-point
+ * numbers, such as "1e3" or "2.0", only when a float64 holds them exactly.
+ */
+func parseInt(s string) (int64, bool) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+	n, ok := number(s)
+	if !ok || n != math.Trunc(n) || math.Abs(n) > maxExact {
+		return 0, false
+	}
+	return int64(n), true
+}
+
+// parseUint is parseInt for unsigned kinds
+func parseUint(s string) (uint64, bool) {
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return n, true
+	}
+	n, ok := number(s)
+	if !ok || n != math.Trunc(n) || n < 0 || n > maxExact {
+		return 0, false
+	}
+	return uint64(n), true
+}
+
+// number parses a valid floating-point number, 0 when empty
+func number(s string) (float64, bool) {
+	if s == "" {
+		return 0, true
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	return n, err == nil
+}
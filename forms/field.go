@@ -0,0 +1,199 @@
+package forms
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+ * A form control described by a struct field's tags:
+ *
+ *	form          name of the control, the field name when empty, "-" to skip
+ *	label         label text, the field name when empty
+ *	help          hint shown below the control
+ *	type          input type, inferred from the field type when empty:
+ *	              text, email, password, search, tel, url, color, hidden,
+ *	              number, range, checkbox, date, datetime-local, month,
+ *	              time, week, textarea, select or radio
+ *	placeholder
+ *	min, max      numbers, or dates in the type's own format, e.g.
+ *	              "2024-01-31" for date or "2024-W05" for week
+ *	step
+ *	minlength, maxlength
+ *	pattern       regular expression the whole value must match
+ *	required      any value but "false"
+ *	options       choices source, see Choices and Chooser; a select or
+ *	              radio value must be one of them, so one without any
+ *	              choices rejects every value
+ *	autocomplete  autofill token, e.g. "email" or "postal-code"
+ *	rows          textarea height
+ */
+type field struct {
+	index []int
+	typ   reflect.Type // Pointers removed
+
+	name         string
+	label        string
+	help         string
+	kind         string
+	placeholder  string
+	min          string
+	max          string
+	step         string
+	minlength    *int
+	maxlength    *int
+	pattern      string
+	required     bool
+	options      string
+	autocomplete string
+	rows         *int
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var cache sync.Map // reflect.Type to []field
+
+// fields lists the controls of a struct type, including those of embedded
+// structs
+func fields(t reflect.Type) []field {
+	if fs, ok := cache.Load(t); ok {
+		return fs.([]field)
+	}
+
+	var fs []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && ft.Kind() == reflect.Struct && ft != timeType {
+			for _, f := range fields(ft) {
+				f.index = append([]int{i}, f.index...)
+				fs = append(fs, f)
+			}
+			continue
+		}
+
+		tag := sf.Tag
+		if tag.Get("form") == "-" {
+			continue
+		}
+
+		f := field{
+			index:        []int{i},
+			typ:          ft,
+			name:         tag.Get("form"),
+			label:        tag.Get("label"),
+			help:         tag.Get("help"),
+			kind:         tag.Get("type"),
+			placeholder:  tag.Get("placeholder"),
+			min:          tag.Get("min"),
+			max:          tag.Get("max"),
+			step:         tag.Get("step"),
+			minlength:    intTag(tag, "minlength"),
+			maxlength:    intTag(tag, "maxlength"),
+			pattern:      tag.Get("pattern"),
+			options:      tag.Get("options"),
+			autocomplete: tag.Get("autocomplete"),
+			rows:         intTag(tag, "rows"),
+		}
+		if required, ok := tag.Lookup("required"); ok && required != "false" {
+			f.required = true
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		if f.label == "" {
+			f.label = sf.Name
+		}
+		if f.kind == "" {
+			f.kind = inferKind(ft, f.options != "")
+		}
+		if f.kind == "" {
+			// No control for this type
+			continue
+		}
+
+		fs = append(fs, f)
+	}
+
+	cache.Store(t, fs)
+	return fs
+}
+
+func inferKind(t reflect.Type, hasOptions bool) string {
+	if hasOptions {
+		return "select"
+	}
+	if t == timeType {
+		return "datetime-local"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "text"
+	case reflect.Bool:
+		return "checkbox"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return ""
+}
+
+func intTag(tag reflect.StructTag, key string) *int {
+	s, ok := tag.Lookup(key)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// multiple reports whether the control holds several values
+func (f field) multiple() bool {
+	return f.typ.Kind() == reflect.Slice
+}
+
+// value finds the field in the struct, nil when behind a nil pointer
+func (f field) value(v reflect.Value) (reflect.Value, bool) {
+	for _, i := range f.index {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// id derives an element ID from the control name
+func (f field) id(prefix string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, f.name)
+}
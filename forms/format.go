@@ -0,0 +1,146 @@
+package forms
+
+// Ref: https://html.spec.whatwg.org/multipage/common-microsyntaxes.html#dates-and-times
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Value layouts of the date and time input types, as the elements
+// package renders them
+var layouts = map[string]string{
+	"date":           "2006-01-02",
+	"datetime-local": "2006-01-02T15:04",
+	"month":          "2006-01",
+	"time":           "15:04",
+}
+
+// Browsers add seconds when the step is below a minute
+var secondsLayouts = map[string]string{
+	"datetime-local": "2006-01-02T15:04:05",
+	"time":           "15:04:05",
+}
+
+func isTimeKind(kind string) bool {
+	_, ok := layouts[kind]
+	return ok || kind == "week"
+}
+
+func formatTime(kind string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if kind == "week" {
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", y, w)
+	}
+	layout, ok := layouts[kind]
+	if !ok {
+		layout = layouts["datetime-local"]
+	}
+	if seconds, ok := secondsLayouts[kind]; ok && t.Second() != 0 {
+		layout = seconds
+	}
+	return t.Format(layout)
+}
+
+var errWeek = errors.New("invalid week")
+
+// parseTime reads a value of a date or time input type, in UTC
+func parseTime(kind, s string) (time.Time, error) {
+	if kind == "week" {
+		return parseWeek(s)
+	}
+
+	layout, ok := layouts[kind]
+	if !ok {
+		layout = layouts["datetime-local"]
+		kind = "datetime-local"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		if seconds, ok := secondsLayouts[kind]; ok {
+			// Fractional seconds are accepted after the layout's seconds
+			if t, err2 := time.Parse(seconds, s); err2 == nil {
+				return t, nil
+			}
+		}
+	}
+	return t, err
+}
+
+// parseWeek reads "2024-W05" as the Monday starting that ISO week
+func parseWeek(s string) (time.Time, error) {
+	if len(s) != 8 || s[4] != '-' || s[5] != 'W' {
+		return time.Time{}, errWeek
+	}
+	year, err := strconv.Atoi(s[:4])
+	if err != nil {
+		return time.Time{}, errWeek
+	}
+	week, err := strconv.Atoi(s[6:])
+	if err != nil || week < 1 {
+		return time.Time{}, errWeek
+	}
+
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	t := monday.AddDate(0, 0, (week-1)*7)
+
+	if y, w := t.ISOWeek(); y != year || w != week {
+		return time.Time{}, errWeek
+	}
+	return t, nil
+}
+
+// format renders a field value the way its control expects it
+func format(kind string, v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		return formatTime(kind, v.Interface().(time.Time))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// formatAll renders each value of a slice field, or the single value
+func formatAll(f field, v reflect.Value) []string {
+	if f.multiple() {
+		out := make([]string, v.Len())
+		for i := range out {
+			out[i] = format(f.kind, v.Index(i))
+		}
+		return out
+	}
+
+	s := format(f.kind, v)
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
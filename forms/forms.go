@@ -0,0 +1,504 @@
+/*
+ * Package forms generates HTML forms from tagged Go structs with the
+ * elements package's builders, filled with the struct's current values.
+ * See field for the tags.
+ *
+ *	type Signup struct {
+ *		Email   string `form:"email" type:"email" label:"Email" required:"true" autocomplete:"email"`
+ *		Country string `form:"country" label:"Country" options:"countries"`
+ *		Age     *int   `form:"age" label:"Age" min:"18" max:"130"`
+ *	}
+ *
+ *	forms.Form(signup, forms.Props{
+ *		FormProps: elements.FormProps{Action: "/signup", Method: elements.FormOptions.Method.Post},
+ *		Choices:   map[string][]forms.Choice{"countries": countries},
+ *		Submit:    "Sign up",
+ *	})
+ *
+ * Optional numbers are best held in pointers, which render empty when nil
+ * rather than as 0. Fractional numbers need a step tag, since browsers
+ * default to whole steps.
+ */
+package forms
+
+import (
+	"html"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * One option of a select or radio group
+ */
+type Choice struct {
+	Value string
+	Label string
+}
+
+// Implemented by structs providing the choices of their options tags
+type Chooser interface {
+	Choices(source string) []Choice
+}
+
+/*
+ * Props
+ */
+type Props struct {
+	// The <form> itself; its InnerHTML is placed after the fields
+	elements.FormProps
+
+	// Choices by options tag, taking precedence over a Chooser
+	Choices map[string][]Choice
+	// Label of the submit button, none when empty
+	Submit string
+	// Prepended to every generated element ID, for pages with several forms
+	IDPrefix string
+	// From Decode, shown next to their fields with the submitted values
+	Errors Errors
+}
+
+// choices finds the options of a select or radio group
+func (props Props) choices(f field, chooser Chooser) []Choice {
+	if f.options == "" {
+		return nil
+	}
+	if c, ok := props.Choices[f.options]; ok {
+		return c
+	}
+	if chooser != nil {
+		return chooser.Choices(f.options)
+	}
+	return nil
+}
+
+// Form renders v's fields inside a <form>
+func Form(v interface{}, props Props) string {
+	fp := props.FormProps
+
+	var b strings.Builder
+	b.WriteString(Fields(v, props))
+	if props.Submit != "" {
+		b.WriteString(elements.Button(elements.ButtonProps{
+			Type:      elements.ButtonOptions.Type.Submit,
+			InnerHTML: html.EscapeString(props.Submit),
+		}))
+	}
+	b.WriteString(fp.InnerHTML)
+	fp.InnerHTML = b.String()
+
+	return elements.Form(fp)
+}
+
+// Fields renders v's fields without the <form>, for composing larger forms
+func Fields(v interface{}, props Props) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+
+	chooser, _ := v.(Chooser)
+
+	var b strings.Builder
+	for _, f := range fields(rv.Type()) {
+		var current []string
+		if fv, ok := f.value(rv); ok {
+			current = formatAll(f, fv)
+		}
+
+		err := props.Errors[f.name]
+		if err != nil {
+			current = err.Values
+		}
+
+		b.WriteString(f.render(props, current, props.choices(f, chooser), err))
+	}
+	return b.String()
+}
+
+/*
+ * render lays a control out with its label, error and help text:
+ *
+ *	<div class="field">
+ *		<label for="email">Email</label>
+ *		<input id="email" aria-invalid="true" aria-describedby="email-error email-help" ...>
+ *		<small id="email-error" class="error">...</small>
+ *		<small id="email-help" class="help">...</small>
+ *	</div>
+ */
+func (f field) render(props Props, current []string, choices []Choice, err *FieldError) string {
+	id := f.id(props.IDPrefix)
+	global := elements.GlobalProps{ID: id}
+
+	var describedby []string
+	var help string
+	if err != nil {
+		errorID := id + "-error"
+		describedby = append(describedby, errorID)
+		help = elements.Small(elements.SmallProps{
+			GlobalProps: elements.GlobalProps{ID: errorID, Class: []string{"error"}},
+			InnerHTML:   html.EscapeString(err.Message),
+		})
+	}
+	if f.help != "" {
+		helpID := id + "-help"
+		describedby = append(describedby, helpID)
+		help += elements.Small(elements.SmallProps{
+			GlobalProps: elements.GlobalProps{ID: helpID, Class: []string{"help"}},
+			InnerHTML:   html.EscapeString(f.help),
+		})
+	}
+
+	if err != nil || len(describedby) > 0 {
+		global.Aria = utils.AriaRoles{}
+		if err != nil {
+			global.Aria["invalid"] = "true"
+		}
+		if len(describedby) > 0 {
+			global.Aria["describedby"] = strings.Join(describedby, " ")
+		}
+	}
+
+	control := f.control(global, current, choices)
+	label := html.EscapeString(f.label)
+
+	var body string
+	switch f.kind {
+	case "hidden":
+		return control
+	case "radio":
+		return elements.Fieldset(elements.FieldsetProps{
+			GlobalProps: elements.GlobalProps{Class: []string{"field"}},
+			InnerHTML:   elements.Legend(elements.LegendProps{InnerHTML: label}) + control + help,
+		})
+	case "checkbox":
+		body = control + elements.Label(elements.LabelProps{For: id, InnerHTML: label})
+	default:
+		body = elements.Label(elements.LabelProps{For: id, InnerHTML: label}) + control
+	}
+
+	return elements.Div(elements.DivProps{
+		GlobalProps: elements.GlobalProps{Class: []string{"field"}},
+		InnerHTML:   body + help,
+	})
+}
+
+// control builds the input, select or textarea itself
+func (f field) control(global elements.GlobalProps, current []string, choices []Choice) string {
+	var value string
+	if len(current) > 0 {
+		value = current[0]
+	}
+	esc := html.EscapeString
+	name := esc(f.name)
+	ac := autocomplete(f.autocomplete)
+
+	switch f.kind {
+	case "checkbox":
+		checked, _ := strconv.ParseBool(value)
+		return elements.InputCheckbox(elements.InputCheckboxProps{
+			GlobalProps: global,
+			Checked:     checked,
+			Name:        name,
+			Required:    f.required,
+			Value:       "true",
+		})
+
+	case "select":
+		var options strings.Builder
+		if f.placeholder != "" && !f.multiple() {
+			// An empty value makes it the placeholder label option, which
+			// required rejects; the builders leave empty attributes out
+			options.WriteString(`<option value="">` + esc(f.placeholder) + `</option>`)
+		}
+		for _, c := range choices {
+			options.WriteString(elements.Option(elements.OptionProps{
+				Value:     esc(c.Value),
+				Selected:  contains(current, c.Value),
+				InnerHTML: esc(c.Label),
+			}))
+		}
+		return elements.Select(elements.SelectProps{
+			GlobalProps:  global,
+			Autocomplete: autocompleteForm(f.autocomplete),
+			Multiple:     f.multiple(),
+			Name:         name,
+			Required:     f.required,
+			InnerHTML:    options.String(),
+		})
+
+	case "radio":
+		var radios strings.Builder
+		for i, c := range choices {
+			radio := elements.GlobalProps{ID: global.ID + "-" + strconv.Itoa(i), Aria: global.Aria}
+			radios.WriteString(elements.Label(elements.LabelProps{
+				InnerHTML: elements.InputRadio(elements.InputRadioProps{
+					GlobalProps: radio,
+					Checked:     c.Value == value,
+					Name:        name,
+					Required:    f.required,
+					Value:       esc(c.Value),
+				}) + " " + esc(c.Label),
+			}))
+		}
+		return radios.String()
+
+	case "textarea":
+		return elements.Textarea(elements.TextareaProps{
+			GlobalProps:  global,
+			Autocomplete: autocompleteForm(f.autocomplete),
+			Maxlength:    f.maxlength,
+			Minlength:    f.minlength,
+			Name:         name,
+			Placeholder:  esc(f.placeholder),
+			Required:     f.required,
+			Rows:         f.rows,
+			InnerHTML:    esc(value),
+		})
+
+	case "range":
+		return withValue(elements.InputRange(elements.InputRangeProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Max:          float(f.max),
+			Min:          float(f.min),
+			Name:         name,
+			Step:         float(f.step),
+		}), value)
+	case "number":
+		return withValue(elements.InputNumber(elements.InputNumberProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Max:          float(f.max),
+			Min:          float(f.min),
+			Name:         name,
+			Placeholder:  esc(f.placeholder),
+			Required:     f.required,
+			Step:         float(f.step),
+		}), value)
+
+	case "date":
+		return elements.InputDate(elements.InputDateProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Max:          f.bound(f.max),
+			Min:          f.bound(f.min),
+			Name:         name,
+			Required:     f.required,
+			Step:         integer(f.step),
+			Value:        esc(value),
+		})
+	case "datetime-local":
+		return elements.InputDatetimeLocal(elements.InputDatetimeLocalProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Max:          f.bound(f.max),
+			Min:          f.bound(f.min),
+			Name:         name,
+			Required:     f.required,
+			Step:         integer(f.step),
+			Value:        esc(value),
+		})
+	case "month":
+		return elements.InputMonth(elements.InputMonthProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Max:          f.bound(f.max),
+			Min:          f.bound(f.min),
+			Name:         name,
+			Required:     f.required,
+			Step:         integer(f.step),
+			Value:        esc(value),
+		})
+	case "time":
+		return withValue(elements.InputTime(elements.InputTimeProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Max:          f.bound(f.max),
+			Min:          f.bound(f.min),
+			Name:         name,
+			Required:     f.required,
+			Step:         integer(f.step),
+		}), value)
+	case "week":
+		return withValue(elements.InputWeek(elements.InputWeekProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Max:          f.bound(f.max),
+			Min:          f.bound(f.min),
+			Name:         name,
+			Required:     f.required,
+			Step:         integer(f.step),
+		}), value)
+
+	case "hidden":
+		return elements.InputHidden(elements.InputHiddenProps{
+			GlobalProps: elements.GlobalProps{ID: global.ID},
+			Name:        name,
+			Value:       esc(value),
+		})
+	case "color":
+		return elements.InputColor(elements.InputColorProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Name:         name,
+			Value:        esc(value),
+		})
+	case "email":
+		return elements.InputEmail(elements.InputEmailProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Maxlength:    f.maxlength,
+			Minlength:    f.minlength,
+			Multiple:     f.multiple(),
+			Name:         name,
+			Pattern:      esc(f.pattern),
+			Placeholder:  esc(f.placeholder),
+			Required:     f.required,
+			Value:        esc(strings.Join(current, ",")),
+		})
+	case "password":
+		// Passwords are never sent back to the browser
+		return elements.InputPassword(elements.InputPasswordProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Maxlength:    f.maxlength,
+			Minlength:    f.minlength,
+			Name:         name,
+			Pattern:      esc(f.pattern),
+			Placeholder:  esc(f.placeholder),
+			Required:     f.required,
+		})
+	case "search":
+		return elements.InputSearch(elements.InputSearchProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Maxlength:    f.maxlength,
+			Minlength:    f.minlength,
+			Name:         name,
+			Pattern:      esc(f.pattern),
+			Placeholder:  esc(f.placeholder),
+			Required:     f.required,
+			Value:        esc(value),
+		})
+	case "tel":
+		return elements.InputTel(elements.InputTelProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Maxlength:    f.maxlength,
+			Minlength:    f.minlength,
+			Name:         name,
+			Pattern:      esc(f.pattern),
+			Placeholder:  esc(f.placeholder),
+			Required:     f.required,
+			Value:        esc(value),
+		})
+	case "url":
+		return elements.InputUrl(elements.InputUrlProps{
+			GlobalProps:  global,
+			Autocomplete: ac,
+			Maxlength:    f.maxlength,
+			Minlength:    f.minlength,
+			Name:         name,
+			Pattern:      esc(f.pattern),
+			Placeholder:  esc(f.placeholder),
+			Required:     f.required,
+			Value:        esc(value),
+		})
+	}
+
+	return elements.InputText(elements.InputTextProps{
+		GlobalProps:  global,
+		Autocomplete: ac,
+		Maxlength:    f.maxlength,
+		Minlength:    f.minlength,
+		Name:         name,
+		Pattern:      esc(f.pattern),
+		Placeholder:  esc(f.placeholder),
+		Required:     f.required,
+		Value:        esc(value),
+	})
+}
+
+// bound parses a date or time in the field's format, zero when empty or
+// invalid
+func (f field) bound(s string) time.Time {
+	t, _ := parseTime(f.kind, s)
+	return t
+}
+
+/*
+ * withValue adds the value attribute to an input built without one. The
+ * time and week builders take a time.Time, which would drop the seconds
+ * of a time and cannot hold a value that failed to parse, and the number
+ * builders a float64, which rounds integers beyond 2^53, so the submitted
+ * or formatted string is written back as it was.
+ */
+func withValue(input, value string) string {
+	if value == "" {
+		return input
+	}
+	return strings.Replace(input, "<input", `<input value="`+html.EscapeString(value)+`"`, 1)
+}
+
+func float(s string) *float64 {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+func integer(s string) *int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Autofill tokens to their InputOptions.Autocomplete option
+var autocompleteTokens = map[string]func() elements.AutocompleteInputOption{}
+
+func init() {
+	options := reflect.ValueOf(elements.InputOptions.Autocomplete)
+	for i := 0; i < options.NumField(); i++ {
+		if fn, ok := options.Field(i).Interface().(func() elements.AutocompleteInputOption); ok && fn != nil {
+			autocompleteTokens[fn().String()] = fn
+		}
+	}
+}
+
+func autocomplete(token string) func() elements.AutocompleteInputOption {
+	return autocompleteTokens[token]
+}
+
+// autocompleteForm maps the tokens <select> and <textarea> accept here
+func autocompleteForm(token string) func() elements.AutocompleteFormOption {
+	switch token {
+	case "on":
+		return elements.FormOptions.Autocomplete.On
+	case "off":
+		return elements.FormOptions.Autocomplete.Off
+	}
+	return nil
+}
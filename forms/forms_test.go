@@ -0,0 +1,170 @@
+package forms
+
+import (
+	"math"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type signup struct {
+	Email    string    `form:"email" type:"email" required:"true"`
+	Password string    `form:"password" type:"password" minlength:"8"`
+	Age      *int      `form:"age" min:"18" max:"130"`
+	Week     time.Time `form:"week" type:"week"`
+	Plan     string    `form:"plan" options:"plans"`
+	Tags     []string  `form:"tags" options:"tags"`
+	Terms    bool      `form:"terms" required:"true"`
+}
+
+var props = Props{Choices: map[string][]Choice{
+	"plans": {{Value: "free"}, {Value: "pro"}},
+	"tags":  {{Value: "a"}, {Value: "b"}},
+}}
+
+func TestDecode(t *testing.T) {
+	var s signup
+	errs, err := DecodeValues(url.Values{
+		"email":    {" ada@example.com\n"},
+		"password": {"correct horse"},
+		"age":      {"36"},
+		"week":     {"2024-W05"},
+		"plan":     {"pro"},
+		"tags":     {"a", "b"},
+		"terms":    {"true"},
+	}, &s, props)
+	if err != nil || errs != nil {
+		t.Fatal(err, errs)
+	}
+
+	if s.Email != "ada@example.com" || s.Age == nil || *s.Age != 36 || s.Plan != "pro" || len(s.Tags) != 2 || !s.Terms {
+		t.Fatalf("unexpected %+v", s)
+	}
+	if !s.Week.Equal(time.Date(2024, time.January, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected week %v", s.Week)
+	}
+	if errs := Validate(s, props); errs != nil {
+		t.Fatal(errs)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var s signup
+	errs, err := DecodeValues(url.Values{
+		"email":    {"ada"},
+		"password": {"short"},
+		"age":      {"17.5"},
+		"week":     {"2024-W99"},
+		"plan":     {"enterprise"},
+	}, &s, props)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"email": "email", "password": "minlength", "age": "min", "plan": "choice", "terms": "required"}
+	for name, rule := range want {
+		if errs[name] == nil || errs[name].Rule != rule {
+			t.Errorf("%s: want %s, got %+v", name, rule, errs[name])
+		}
+	}
+	if errs["password"].Values != nil {
+		t.Error("password was kept for re-rendering")
+	}
+
+	html := Fields(s, Props{Choices: props.Choices, Errors: errs})
+	if !strings.Contains(html, `value="ada"`) || !strings.Contains(html, `aria-invalid="true"`) || !strings.Contains(html, `id="email-error"`) {
+		t.Fatalf("errors not rendered: %s", html)
+	}
+	if errs["week"] == nil || !strings.Contains(html, `value="2024-W99"`) {
+		t.Fatalf("submitted week not kept: %+v %s", errs["week"], html)
+	}
+}
+
+func TestChoiceWithoutChoices(t *testing.T) {
+	var s struct {
+		Plan string `form:"plan" type:"radio" options:"missing"`
+		Size string `form:"size" type:"select"`
+	}
+	errs, err := DecodeValues(url.Values{"plan": {"pro"}, "size": {"xl"}}, &s, Props{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"plan", "size"} {
+		if errs[name] == nil || errs[name].Rule != "choice" {
+			t.Errorf("%s: accepted without choices: %+v", name, errs[name])
+		}
+	}
+
+	// Unset optional fields still pass
+	errs, _ = DecodeValues(url.Values{}, &s, Props{})
+	if errs != nil {
+		t.Errorf("got %v", errs)
+	}
+}
+
+func TestDecodeIntegers(t *testing.T) {
+	var s struct {
+		ID    int64  `form:"id"`
+		Count uint64 `form:"count"`
+		Small int8   `form:"small"`
+		Sci   int    `form:"sci"`
+	}
+	errs, err := DecodeValues(url.Values{
+		"id":    {"9007199254740993"},
+		"count": {"18446744073709551615"},
+		"small": {"-128"},
+		"sci":   {"1e3"},
+	}, &s, Props{})
+	if err != nil || errs != nil {
+		t.Fatal(err, errs)
+	}
+	if s.ID != 9007199254740993 || s.Count != math.MaxUint64 || s.Small != -128 || s.Sci != 1000 {
+		t.Fatalf("unexpected %+v", s)
+	}
+
+	html := Fields(s, Props{})
+	if !strings.Contains(html, `value="9007199254740993"`) || !strings.Contains(html, `value="18446744073709551615"`) {
+		t.Errorf("rounded on re-render: %s", html)
+	}
+
+	for name, v := range map[string]string{
+		"id":    "9223372036854775808",
+		"count": "-1",
+		"small": "128",
+		"sci":   "1.5",
+	} {
+		errs, _ := DecodeValues(url.Values{name: {v}}, &s, Props{})
+		if errs[name] == nil {
+			t.Errorf("%s=%s: got %+v", name, v, errs[name])
+		}
+	}
+}
+
+func TestTimeStepFarDates(t *testing.T) {
+	var s struct {
+		At   time.Time `form:"at" type:"datetime-local"`
+		Week time.Time `form:"week" type:"week"`
+		Day  time.Time `form:"day" type:"date" step:"2"`
+	}
+	// Past the ~292 years a time.Duration spans from 1970
+	errs, err := DecodeValues(url.Values{
+		"at":   {"2300-06-01T12:30"},
+		"week": {"2300-W10"},
+		"day":  {"2300-01-03"},
+	}, &s, Props{})
+	if err != nil || errs != nil {
+		t.Fatal(err, errs)
+	}
+
+	errs, _ = DecodeValues(url.Values{
+		"at":  {"2300-06-01T12:30:30"},
+		"day": {"2300-01-02"},
+	}, &s, Props{})
+	if e := errs["at"]; e == nil || e.Rule != "step" || e.Param != "2300-06-01T12:30 and 2300-06-01T12:31" {
+		t.Errorf("at: got %+v", e)
+	}
+	if e := errs["day"]; e == nil || e.Rule != "step" || e.Param != "2300-01-01 and 2300-01-03" {
+		t.Errorf("day: got %+v", e)
+	}
+}
@@ -0,0 +1,369 @@
+package forms
+
+// Ref: https://html.spec.whatwg.org/multipage/input.html#the-input-element
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+)
+
+/*
+ * Messages for each failed constraint, by FieldError.Rule, worded like the
+ * browsers' own. "{param}" is replaced with the constraint's value.
+ * Replace entries to translate them.
+ */
+var Messages = map[string]string{
+	"required":  "Please fill out this field.",
+	"badInput":  "Please enter a valid value.",
+	"email":     "Please enter an email address.",
+	"url":       "Please enter a URL.",
+	"min":       "Value must be greater than or equal to {param}.",
+	"max":       "Value must be less than or equal to {param}.",
+	"minDate":   "Value must be {param} or later.",
+	"maxDate":   "Value must be {param} or earlier.",
+	"step":      "Please enter a valid value. The nearest valid values are {param}.",
+	"minlength": "Please use at least {param} characters.",
+	"maxlength": "Please use no more than {param} characters.",
+	"pattern":   "Please match the requested format.",
+	"choice":    "Please select one of the listed options.",
+}
+
+/*
+ * A field that failed a constraint
+ */
+type FieldError struct {
+	// Control name, the form tag
+	Name string
+	// Failed constraint, a key of Messages
+	Rule string
+	// Constraint value, e.g. the minimum
+	Param   string
+	Message string
+	// Submitted values, shown again when the form is re-rendered
+	Values []string
+}
+
+func (e *FieldError) Error() string {
+	return e.Name + ": " + e.Message
+}
+
+/*
+ * Errors by control name. A nil or empty Errors means the form is valid.
+ */
+type Errors map[string]*FieldError
+
+func (e Errors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = e[name].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func newError(f field, rule, param string, values []string) *FieldError {
+	msg := strings.ReplaceAll(Messages[rule], "{param}", param)
+	if f.kind == "password" {
+		values = nil
+	}
+	return &FieldError{Name: f.name, Rule: rule, Param: param, Message: msg, Values: values}
+}
+
+// sanitize applies the value sanitization algorithm of the control type
+func sanitize(kind, s string) string {
+	switch kind {
+	case "email", "url":
+		return strings.TrimSpace(stripNewlines(s))
+	case "text", "search", "tel", "password", "hidden":
+		return stripNewlines(s)
+	case "textarea":
+		return strings.ReplaceAll(s, "\r\n", "\n")
+	case "color":
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+var (
+	validFloat = regexp.MustCompile(`^-?(?:[0-9]+(?:\.[0-9]+)?|\.[0-9]+)(?:[eE][+-]?[0-9]+)?$`)
+	validEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+	validColor = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+)
+
+/*
+ * check enforces the field's constraints on submitted values the way a
+ * browser does, so a form that passes client-side validation also passes
+ * here. Values are already sanitized.
+ */
+func (f field) check(values []string, choices []Choice) *FieldError {
+	if f.kind == "checkbox" {
+		if f.required && (len(values) == 0 || values[0] == "") {
+			return newError(f, "required", "", values)
+		}
+		return nil
+	}
+
+	nonEmpty := values[:0:0]
+	for _, v := range values {
+		if v != "" {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		if f.required {
+			return newError(f, "required", "", values)
+		}
+		return nil
+	}
+	if !f.multiple() && f.kind != "email" {
+		nonEmpty = nonEmpty[:1]
+	}
+
+	for _, v := range nonEmpty {
+		if err := f.checkOne(v, choices, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f field) checkOne(v string, choices []Choice, values []string) *FieldError {
+	switch {
+	case f.kind == "select" || f.kind == "radio":
+		// With no choices configured nothing can be chosen, so a missing
+		// options source fails closed rather than accepting any value
+		if !chosen(choices, v) {
+			return newError(f, "choice", "", values)
+		}
+		return nil
+
+	case f.kind == "number" || f.kind == "range":
+		return f.checkNumber(v, values)
+
+	case isTimeKind(f.kind):
+		return f.checkTime(v, values)
+
+	case f.kind == "color":
+		if !validColor.MatchString(v) {
+			return newError(f, "badInput", "", values)
+		}
+		return nil
+
+	case f.kind == "email":
+		// With multiple, every comma separated address must be valid
+		addresses := []string{v}
+		if f.multiple() {
+			addresses = strings.Split(v, ",")
+		}
+		for _, a := range addresses {
+			a = strings.TrimSpace(a)
+			if !validEmail.MatchString(a) {
+				return newError(f, "email", "", values)
+			}
+			if err := f.checkText(a, values); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case f.kind == "url":
+		if u, err := url.Parse(v); err != nil || u.Scheme == "" {
+			return newError(f, "url", "", values)
+		}
+	}
+
+	return f.checkText(v, values)
+}
+
+func (f field) checkText(v string, values []string) *FieldError {
+	// Lengths are counted in UTF-16 code units, as in JavaScript
+	n := len(utf16.Encode([]rune(v)))
+	if f.minlength != nil && n < *f.minlength {
+		return newError(f, "minlength", strconv.Itoa(*f.minlength), values)
+	}
+	if f.maxlength != nil && n > *f.maxlength {
+		return newError(f, "maxlength", strconv.Itoa(*f.maxlength), values)
+	}
+	if f.pattern != "" && f.kind != "textarea" {
+		if re := compilePattern(f.pattern); re != nil && !re.MatchString(v) {
+			return newError(f, "pattern", f.pattern, values)
+		}
+	}
+	return nil
+}
+
+func (f field) checkNumber(v string, values []string) *FieldError {
+	if !validFloat.MatchString(v) {
+		return newError(f, "badInput", "", values)
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsInf(n, 0) {
+		return newError(f, "badInput", "", values)
+	}
+
+	min, hasMin := parseFloat(f.min)
+	max, hasMax := parseFloat(f.max)
+	if hasMin && n < min {
+		return newError(f, "min", f.min, values)
+	}
+	if hasMax && n > max {
+		return newError(f, "max", f.max, values)
+	}
+
+	step := 1.0
+	if f.step == "any" {
+		return nil
+	}
+	if s, ok := parseFloat(f.step); ok && s > 0 {
+		step = s
+	}
+	base := 0.0
+	if hasMin {
+		base = min
+	}
+
+	q := (n - base) / step
+	if math.Abs(q-math.Round(q)) > 1e-9 {
+		lower := base + math.Floor(q)*step
+		return newError(f, "step", trimFloat(lower)+" and "+trimFloat(lower+step), values)
+	}
+	return nil
+}
+
+func (f field) checkTime(v string, values []string) *FieldError {
+	t, err := parseTime(f.kind, v)
+	if err != nil {
+		return newError(f, "badInput", "", values)
+	}
+
+	min, minErr := parseTime(f.kind, f.min)
+	max, maxErr := parseTime(f.kind, f.max)
+	if f.min != "" && minErr == nil && t.Before(min) {
+		return newError(f, "minDate", f.min, values)
+	}
+	if f.max != "" && maxErr == nil && t.After(max) {
+		return newError(f, "maxDate", f.max, values)
+	}
+
+	if f.step == "any" {
+		return nil
+	}
+	base := timeStepBase(f.kind)
+	if f.min != "" && minErr == nil {
+		base = min
+	}
+	step := int64(1)
+	if s, err := strconv.ParseInt(f.step, 10, 64); err == nil && s > 0 {
+		step = s
+	} else if f.kind == "time" || f.kind == "datetime-local" {
+		step = 60
+	}
+
+	// Distance from the base in units of the type: months, days, weeks or
+	// seconds. Counted from Unix seconds, as time.Duration only spans about
+	// 292 years.
+	const day = 24 * 60 * 60
+	var diff int64
+	exact := true
+	switch f.kind {
+	case "month":
+		diff = int64((t.Year()-base.Year())*12 + int(t.Month()-base.Month()))
+	case "date":
+		diff = (t.Unix() - base.Unix()) / day
+	case "week":
+		diff = (t.Unix() - base.Unix()) / (7 * day)
+	default:
+		diff = t.Unix() - base.Unix()
+		exact = t.Nanosecond() == base.Nanosecond()
+		if t.Nanosecond() < base.Nanosecond() {
+			diff--
+		}
+	}
+
+	if exact && diff%step == 0 {
+		return nil
+	}
+
+	lower := diff / step * step
+	if diff < 0 && diff%step != 0 {
+		lower -= step
+	}
+	nearest := func(units int64) string {
+		switch f.kind {
+		case "month":
+			return formatTime(f.kind, base.AddDate(0, int(units), 0))
+		case "date":
+			return formatTime(f.kind, base.AddDate(0, 0, int(units)))
+		case "week":
+			return formatTime(f.kind, base.AddDate(0, 0, int(units)*7))
+		}
+		return formatTime(f.kind, time.Unix(base.Unix()+units, int64(base.Nanosecond())).In(base.Location()))
+	}
+	return newError(f, "step", nearest(lower)+" and "+nearest(lower+step), values)
+}
+
+// timeStepBase is the default step base of each type
+func timeStepBase(kind string) time.Time {
+	switch kind {
+	case "time":
+		t, _ := time.Parse("15:04", "00:00")
+		return t
+	case "week":
+		// 1970-W01 starts on Monday December 29th 1969
+		return time.Date(1969, time.December, 29, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func chosen(choices []Choice, v string) bool {
+	for _, c := range choices {
+		if c.Value == v {
+			return true
+		}
+	}
+	return false
+}
+
+var patterns sync.Map // string to *regexp.Regexp, nil when invalid
+
+// compilePattern anchors a pattern attribute as browsers do. Browsers
+// ignore invalid patterns, and so does this.
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		re = nil
+	}
+	patterns.Store(pattern, re)
+	return re
+}
+
+func parseFloat(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	return n, err == nil
+}
+
+func trimFloat(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,131 @@
+package httpcache
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Accept-Encoding
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"strconv"
+	"strings"
+)
+
+const (
+	encodingIdentity = "identity"
+	encodingGzip     = "gzip"
+	encodingDeflate  = "deflate"
+)
+
+// Server preference when the client weighs encodings equally
+var supportedEncodings = []string{encodingGzip, encodingDeflate}
+
+// negotiateEncoding picks the best supported content-coding from an
+// Accept-Encoding header, falling back to identity
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return encodingIdentity
+	}
+
+	weights := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, q := parseQuality(part)
+		if name == "" {
+			continue
+		}
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+		weights[name] = q
+	}
+
+	best := encodingIdentity
+	bestQ := 0.0
+	for _, enc := range supportedEncodings {
+		q, ok := weights[enc]
+		if !ok {
+			if wildcard < 0 {
+				continue
+			}
+			q = wildcard
+		}
+		if q > bestQ {
+			best = enc
+			bestQ = q
+		}
+	}
+
+	return best
+}
+
+func parseQuality(part string) (string, float64) {
+	fields := strings.Split(part, ";")
+	name := strings.ToLower(strings.TrimSpace(fields[0]))
+	q := 1.0
+	for _, param := range fields[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+		v, err := strconv.ParseFloat(param[2:], 64)
+		if err == nil {
+			q = v
+		}
+	}
+	return name, q
+}
+
+// encode compresses body with the named content-coding. HTTP "deflate" is
+// the zlib format (RFC 1950), not a raw deflate stream.
+func encode(encoding string, level int, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch encoding {
+	case encodingGzip:
+		w, err := gzip.NewWriterLevel(&buf, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(body); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	case encodingDeflate:
+		w, err := zlib.NewWriterLevel(&buf, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(body); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return body, nil
+	}
+
+	return buf.Bytes(), nil
+}
+
+// compressible reports whether a media type benefits from compression
+func compressible(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	if strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,252 @@
+/*
+ * Package httpcache provides HTTP caching middleware for Mx handlers. The
+ * rendered body is buffered, hashed into a strong ETag, answered with
+ * 304 Not Modified when the client already holds it, decorated with a
+ * per-route Cache-Control policy and compressed with gzip or deflate.
+ */
+package httpcache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default smallest body worth compressing, in bytes
+const DefaultMinSize = 1024
+
+/*
+ * Options
+ */
+type Options struct {
+	// Policy used when no route matches
+	Default Policy
+	// Per-route policies, see Routes
+	Routes Routes
+	// Supplies Last-Modified for responses that do not set it themselves
+	LastModified func(r *http.Request) time.Time
+	// Smallest body compressed, DefaultMinSize when zero
+	MinSize int
+	// gzip/zlib compression level, gzip.DefaultCompression when zero
+	Level int
+}
+
+// Middleware returns Handler as a chainable middleware
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return Handler(next, opts)
+	}
+}
+
+/*
+ * Handler wraps next with ETag, Last-Modified, Cache-Control and
+ * compression handling. Responses always vary on HX-Request, since htmx
+ * requests usually receive a fragment instead of the full page, and on
+ * Accept-Encoding whenever they are eligible for compression.
+ *
+ * The response is buffered in full, so streaming endpoints (Server-Sent
+ * Events and WebSocket upgrades) are passed straight through.
+ */
+func Handler(next http.Handler, opts Options) http.Handler {
+	if opts.MinSize == 0 {
+		opts.MinSize = DefaultMinSize
+	}
+	if opts.Level == 0 {
+		opts.Level = gzip.DefaultCompression
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if streaming(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{header: http.Header{}}
+		next.ServeHTTP(rec, r)
+
+		opts.respond(w, r, rec)
+	})
+}
+
+func (opts Options) respond(w http.ResponseWriter, r *http.Request, rec *recorder) {
+	h := w.Header()
+	for k, v := range rec.header {
+		h[k] = v
+	}
+
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := rec.body.Bytes()
+
+	AddVary(h, "HX-Request")
+
+	if !bodyAllowed(status) {
+		w.WriteHeader(status)
+		return
+	}
+
+	if h.Get("Content-Type") == "" && len(body) > 0 {
+		h.Set("Content-Type", http.DetectContentType(body))
+	}
+
+	encoding := encodingIdentity
+	if h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) && len(body) >= opts.MinSize {
+		AddVary(h, "Accept-Encoding")
+		encoding = negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	}
+
+	cacheable := (r.Method == http.MethodGet || r.Method == http.MethodHead) && status == http.StatusOK
+	if cacheable {
+		if h.Get("Cache-Control") == "" {
+			policy, ok := opts.Routes.lookup(r.URL.Path)
+			if !ok {
+				policy = opts.Default
+			}
+			h.Set("Cache-Control", policy.String())
+		}
+
+		if h.Get("Last-Modified") == "" && opts.LastModified != nil {
+			if t := opts.LastModified(r); !t.IsZero() {
+				h.Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+			}
+		}
+
+		etag := h.Get("ETag")
+		if etag == "" {
+			etag = strongETag(body)
+		}
+		etag = representationETag(etag, encoding)
+		h.Set("ETag", etag)
+
+		if notModified(r, etag, h.Get("Last-Modified")) {
+			h.Del("Content-Type")
+			h.Del("Content-Length")
+			h.Del("Content-Encoding")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	if encoding != encodingIdentity {
+		encoded, err := encode(encoding, opts.Level, body)
+		if err == nil {
+			body = encoded
+			h.Set("Content-Encoding", encoding)
+		}
+	}
+
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+/*
+ * Buffers a handler's response so it can be hashed and compressed before
+ * anything reaches the client.
+ */
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(b)
+}
+
+// strongETag hashes the rendered body
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:18]) + `"`
+}
+
+// representationETag distinguishes encoded representations, since a strong
+// validator must change whenever the bytes on the wire do
+func representationETag(etag, encoding string) string {
+	if encoding == encodingIdentity || strings.HasPrefix(etag, "W/") || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + "-" + encoding + `"`
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since
+// only when no entity tags were sent
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatch(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// etagMatch uses the weak comparison required for If-None-Match
+func etagMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// AddVary appends a field name to Vary unless already listed
+func AddVary(h http.Header, field string) {
+	for _, v := range h.Values("Vary") {
+		for _, existing := range strings.Split(v, ",") {
+			existing = strings.TrimSpace(existing)
+			if existing == "*" || strings.EqualFold(existing, field) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", field)
+}
+
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+func streaming(r *http.Request) bool {
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		return true
+	}
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
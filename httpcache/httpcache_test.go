@@ -0,0 +1,137 @@
+package httpcache
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var page = "<!DOCTYPE html><html><body>" + strings.Repeat("<p>Mx</p>", 200) + "</body></html>"
+
+func pageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, page)
+	})
+}
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestETagNotModified(t *testing.T) {
+	h := Handler(pageHandler(), Options{})
+
+	first := serve(h, httptest.NewRequest("GET", "/", nil))
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", first.Code, etag)
+	}
+	if first.Body.String() != page {
+		t.Fatal("identity body was altered")
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("If-None-Match", `"other", W/`+etag)
+	second := serve(h, r)
+	if second.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", second.Code)
+	}
+	if second.Body.Len() != 0 {
+		t.Fatal("304 must not carry a body")
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	modified := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	h := Handler(pageHandler(), Options{
+		LastModified: func(r *http.Request) time.Time { return modified },
+	})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("If-Modified-Since", modified.Add(time.Hour).Format(http.TimeFormat))
+	if w := serve(h, r); w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", w.Code)
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat))
+	if w := serve(h, r); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	h := Handler(pageHandler(), Options{})
+
+	tests := []struct {
+		accept   string
+		encoding string
+	}{
+		{"gzip, deflate", "gzip"},
+		{"deflate, gzip;q=0.5", "deflate"},
+		{"gzip;q=0, br", ""},
+		{"*", "gzip"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Encoding", test.accept)
+		w := serve(h, r)
+
+		if got := w.Header().Get("Content-Encoding"); got != test.encoding {
+			t.Errorf("%q: expected encoding %q, got %q", test.accept, test.encoding, got)
+			continue
+		}
+		vary := strings.Join(w.Header().Values("Vary"), ", ")
+		if !strings.Contains(vary, "Accept-Encoding") || !strings.Contains(vary, "HX-Request") {
+			t.Errorf("%q: unexpected Vary %q", test.accept, vary)
+		}
+
+		var body io.Reader = w.Body
+		switch test.encoding {
+		case "gzip":
+			body, _ = gzip.NewReader(w.Body)
+		case "deflate":
+			body, _ = zlib.NewReader(w.Body)
+		}
+		b, _ := io.ReadAll(body)
+		if string(b) != page {
+			t.Errorf("%q: body did not round-trip", test.accept)
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	h := Handler(pageHandler(), Options{
+		Default: Revalidate,
+		Routes: Routes{
+			"/assets/":      Immutable,
+			"/assets/admin": NoStore,
+			"/about":        {Public: true, MaxAge: time.Hour},
+		},
+	})
+
+	tests := map[string]string{
+		"/":             "no-cache",
+		"/about":        "public, max-age=3600",
+		"/about/team":   "no-cache",
+		"/assets/a.css": "public, immutable, max-age=31536000",
+		"/assets/admin": "no-store",
+	}
+
+	for path, expected := range tests {
+		w := serve(h, httptest.NewRequest("GET", path, nil))
+		if got := w.Header().Get("Cache-Control"); got != expected {
+			t.Errorf("%s: expected %q, got %q", path, expected, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package httpcache
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Cache-Control
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ * Describes the Cache-Control response directives for a route. The zero
+ * value sends "no-cache", which still lets clients revalidate with the
+ * ETag but never serves a stored copy without asking first.
+ */
+type Policy struct {
+	Public               bool
+	Private              bool
+	NoCache              bool
+	NoStore              bool
+	NoTransform          bool
+	MustRevalidate       bool
+	ProxyRevalidate      bool
+	Immutable            bool
+	MaxAge               time.Duration
+	SMaxAge              time.Duration
+	StaleWhileRevalidate time.Duration
+	StaleIfError         time.Duration
+}
+
+// Common policies
+var (
+	// Revalidate on every request, relying on ETag/Last-Modified for 304s
+	Revalidate = Policy{NoCache: true}
+
+	// Never store the response anywhere
+	NoStore = Policy{NoStore: true}
+
+	// Fingerprinted assets that never change under the same URL
+	Immutable = Policy{Public: true, MaxAge: 365 * 24 * time.Hour, Immutable: true}
+)
+
+// String renders the Cache-Control header value
+func (p Policy) String() string {
+	var d []string
+
+	if p.Public {
+		d = append(d, "public")
+	}
+	if p.Private {
+		d = append(d, "private")
+	}
+	if p.NoCache {
+		d = append(d, "no-cache")
+	}
+	if p.NoStore {
+		d = append(d, "no-store")
+	}
+	if p.NoTransform {
+		d = append(d, "no-transform")
+	}
+	if p.MustRevalidate {
+		d = append(d, "must-revalidate")
+	}
+	if p.ProxyRevalidate {
+		d = append(d, "proxy-revalidate")
+	}
+	if p.Immutable {
+		d = append(d, "immutable")
+	}
+	d = appendSeconds(d, "max-age", p.MaxAge)
+	d = appendSeconds(d, "s-maxage", p.SMaxAge)
+	d = appendSeconds(d, "stale-while-revalidate", p.StaleWhileRevalidate)
+	d = appendSeconds(d, "stale-if-error", p.StaleIfError)
+
+	if len(d) == 0 {
+		return "no-cache"
+	}
+
+	return strings.Join(d, ", ")
+}
+
+func appendSeconds(d []string, name string, v time.Duration) []string {
+	if v <= 0 {
+		return d
+	}
+	return append(d, name+"="+strconv.FormatInt(int64(v/time.Second), 10))
+}
+
+/*
+ * Maps request paths to policies. Patterns follow http.ServeMux rules: a
+ * pattern ending in "/" matches the whole subtree, any other pattern
+ * matches only that exact path. The longest matching pattern wins.
+ */
+type Routes map[string]Policy
+
+func (r Routes) lookup(path string) (Policy, bool) {
+	var (
+		best  string
+		found bool
+	)
+	for pattern := range r {
+		if !matchPattern(pattern, path) {
+			continue
+		}
+		if !found || len(pattern) > len(best) {
+			best = pattern
+			found = true
+		}
+	}
+	return r[best], found
+}
+
+func matchPattern(pattern, path string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(path, pattern)
+	}
+	return pattern == path
+}
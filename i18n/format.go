@@ -0,0 +1,466 @@
+package i18n
+
+// Ref: https://cldr.unicode.org/translation/number-currency-formats
+// Ref: https://cldr.unicode.org/translation/date-time
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ * Format holds how a locale writes numbers, dates and relative times.
+ * Patterns fill {n}, {symbol}, {day}, {month}, {year} and {time}.
+ */
+type Format struct {
+	Decimal string
+	Group   string
+	// e.g. "{symbol}{n}" or "{n}\u00a0{symbol}", with a no-break space
+	Currency string
+	// e.g. "{n}%" or "{n}\u00a0%"
+	Percent string
+
+	// Month names as they read in a long date, in the genitive where the
+	// language inflects them
+	Months [12]string
+	// Go layout, e.g. "1/2/2006"
+	ShortDate string
+	// e.g. "{month} {day}, {year}"
+	LongDate string
+	// Go layout, e.g. "3:04 PM"
+	Time string
+
+	// Relative times: the word for now, the past and future patterns
+	// around {time}, and the plural forms of each unit around {count}.
+	// Locales without units use the English ones.
+	Now    string
+	Past   string
+	Future string
+	Units  map[string]map[string]string
+}
+
+/*
+ * Formats of common locales, by tag or base language. Locales missing
+ * here use their base language, then "en"; add entries to support others.
+ */
+var Formats = map[string]*Format{}
+
+// Currency symbols by ISO 4217 code; other codes are written as is
+var CurrencySymbols = map[string]string{
+	"AUD": "A$", "BRL": "R$", "CAD": "CA$", "CHF": "CHF", "CNY": "¥", "EUR": "€",
+	"GBP": "£", "INR": "₹", "JPY": "¥", "KRW": "₩", "PLN": "zł", "RUB": "₽", "USD": "$",
+}
+
+// Currencies without minor units
+var wholeCurrencies = map[string]bool{"JPY": true, "KRW": true}
+
+func oneOther(one, other string) map[string]string {
+	return map[string]string{One: one, Other: other}
+}
+
+func oneFewMany(one, few, many string) map[string]string {
+	return map[string]string{One: one, Few: few, Many: many, Other: few}
+}
+
+func other(other string) map[string]string {
+	return map[string]string{Other: other}
+}
+
+func init() {
+	en := &Format{
+		Decimal: ".", Group: ",", Currency: "{symbol}{n}", Percent: "{n}%",
+		Months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		ShortDate: "1/2/2006", LongDate: "{month} {day}, {year}", Time: "3:04 PM",
+		Now: "now", Past: "{time} ago", Future: "in {time}",
+		Units: map[string]map[string]string{
+			"second": oneOther("{count} second", "{count} seconds"),
+			"minute": oneOther("{count} minute", "{count} minutes"),
+			"hour":   oneOther("{count} hour", "{count} hours"),
+			"day":    oneOther("{count} day", "{count} days"),
+			"week":   oneOther("{count} week", "{count} weeks"),
+			"month":  oneOther("{count} month", "{count} months"),
+			"year":   oneOther("{count} year", "{count} years"),
+		},
+	}
+	Formats["en"] = en
+
+	gb := *en
+	gb.ShortDate, gb.LongDate, gb.Time = "02/01/2006", "{day} {month} {year}", "15:04"
+	Formats["en-GB"] = &gb
+
+	Formats["fr"] = &Format{
+		Decimal: ",", Group: "\u202f", Currency: "{n}\u00a0{symbol}", Percent: "{n}\u202f%",
+		Months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortDate: "02/01/2006", LongDate: "{day} {month} {year}", Time: "15:04",
+		Now: "maintenant", Past: "il y a {time}", Future: "dans {time}",
+		Units: map[string]map[string]string{
+			"second": oneOther("{count} seconde", "{count} secondes"),
+			"minute": oneOther("{count} minute", "{count} minutes"),
+			"hour":   oneOther("{count} heure", "{count} heures"),
+			"day":    oneOther("{count} jour", "{count} jours"),
+			"week":   oneOther("{count} semaine", "{count} semaines"),
+			"month":  oneOther("{count} mois", "{count} mois"),
+			"year":   oneOther("{count} an", "{count} ans"),
+		},
+	}
+
+	de := &Format{
+		Decimal: ",", Group: ".", Currency: "{n}\u00a0{symbol}", Percent: "{n}\u00a0%",
+		Months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortDate: "02.01.2006", LongDate: "{day}. {month} {year}", Time: "15:04",
+		Now: "jetzt", Past: "vor {time}", Future: "in {time}",
+		Units: map[string]map[string]string{
+			"second": oneOther("{count} Sekunde", "{count} Sekunden"),
+			"minute": oneOther("{count} Minute", "{count} Minuten"),
+			"hour":   oneOther("{count} Stunde", "{count} Stunden"),
+			"day":    oneOther("{count} Tag", "{count} Tagen"),
+			"week":   oneOther("{count} Woche", "{count} Wochen"),
+			"month":  oneOther("{count} Monat", "{count} Monaten"),
+			"year":   oneOther("{count} Jahr", "{count} Jahren"),
+		},
+	}
+	Formats["de"] = de
+
+	ch := *de
+	ch.Decimal, ch.Group, ch.Currency, ch.Percent = ".", "’", "{symbol}\u00a0{n}", "{n}%"
+	Formats["de-CH"] = &ch
+
+	Formats["es"] = &Format{
+		Decimal: ",", Group: ".", Currency: "{n}\u00a0{symbol}", Percent: "{n}\u00a0%",
+		Months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortDate: "2/1/2006", LongDate: "{day} de {month} de {year}", Time: "15:04",
+		Now: "ahora", Past: "hace {time}", Future: "dentro de {time}",
+		Units: map[string]map[string]string{
+			"second": oneOther("{count} segundo", "{count} segundos"),
+			"minute": oneOther("{count} minuto", "{count} minutos"),
+			"hour":   oneOther("{count} hora", "{count} horas"),
+			"day":    oneOther("{count} día", "{count} días"),
+			"week":   oneOther("{count} semana", "{count} semanas"),
+			"month":  oneOther("{count} mes", "{count} meses"),
+			"year":   oneOther("{count} año", "{count} años"),
+		},
+	}
+
+	Formats["it"] = &Format{
+		Decimal: ",", Group: ".", Currency: "{n}\u00a0{symbol}", Percent: "{n}%",
+		Months: [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+			"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		ShortDate: "02/01/2006", LongDate: "{day} {month} {year}", Time: "15:04",
+		Now: "ora", Past: "{time} fa", Future: "tra {time}",
+		Units: map[string]map[string]string{
+			"second": oneOther("{count} secondo", "{count} secondi"),
+			"minute": oneOther("{count} minuto", "{count} minuti"),
+			"hour":   oneOther("{count} ora", "{count} ore"),
+			"day":    oneOther("{count} giorno", "{count} giorni"),
+			"week":   oneOther("{count} settimana", "{count} settimane"),
+			"month":  oneOther("{count} mese", "{count} mesi"),
+			"year":   oneOther("{count} anno", "{count} anni"),
+		},
+	}
+
+	Formats["pt"] = &Format{
+		Decimal: ",", Group: ".", Currency: "{symbol}\u00a0{n}", Percent: "{n}%",
+		Months: [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+			"julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortDate: "02/01/2006", LongDate: "{day} de {month} de {year}", Time: "15:04",
+		Now: "agora", Past: "há {time}", Future: "em {time}",
+		Units: map[string]map[string]string{
+			"second": oneOther("{count} segundo", "{count} segundos"),
+			"minute": oneOther("{count} minuto", "{count} minutos"),
+			"hour":   oneOther("{count} hora", "{count} horas"),
+			"day":    oneOther("{count} dia", "{count} dias"),
+			"week":   oneOther("{count} semana", "{count} semanas"),
+			"month":  oneOther("{count} mês", "{count} meses"),
+			"year":   oneOther("{count} ano", "{count} anos"),
+		},
+	}
+
+	Formats["nl"] = &Format{
+		Decimal: ",", Group: ".", Currency: "{symbol}\u00a0{n}", Percent: "{n}%",
+		Months: [12]string{"januari", "februari", "maart", "april", "mei", "juni",
+			"juli", "augustus", "september", "oktober", "november", "december"},
+		ShortDate: "2-1-2006", LongDate: "{day} {month} {year}", Time: "15:04",
+		Now: "nu", Past: "{time} geleden", Future: "over {time}",
+		Units: map[string]map[string]string{
+			"second": oneOther("{count} seconde", "{count} seconden"),
+			"minute": oneOther("{count} minuut", "{count} minuten"),
+			"hour":   oneOther("{count} uur", "{count} uur"),
+			"day":    oneOther("{count} dag", "{count} dagen"),
+			"week":   oneOther("{count} week", "{count} weken"),
+			"month":  oneOther("{count} maand", "{count} maanden"),
+			"year":   oneOther("{count} jaar", "{count} jaar"),
+		},
+	}
+
+	Formats["ru"] = &Format{
+		Decimal: ",", Group: "\u00a0", Currency: "{n}\u00a0{symbol}", Percent: "{n}\u00a0%",
+		Months: [12]string{"января", "февраля", "марта", "апреля", "мая", "июня",
+			"июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		ShortDate: "02.01.2006", LongDate: "{day} {month} {year} г.", Time: "15:04",
+		Now: "сейчас", Past: "{time} назад", Future: "через {time}",
+		Units: map[string]map[string]string{
+			"second": oneFewMany("{count} секунду", "{count} секунды", "{count} секунд"),
+			"minute": oneFewMany("{count} минуту", "{count} минуты", "{count} минут"),
+			"hour":   oneFewMany("{count} час", "{count} часа", "{count} часов"),
+			"day":    oneFewMany("{count} день", "{count} дня", "{count} дней"),
+			"week":   oneFewMany("{count} неделю", "{count} недели", "{count} недель"),
+			"month":  oneFewMany("{count} месяц", "{count} месяца", "{count} месяцев"),
+			"year":   oneFewMany("{count} год", "{count} года", "{count} лет"),
+		},
+	}
+
+	Formats["pl"] = &Format{
+		Decimal: ",", Group: "\u00a0", Currency: "{n}\u00a0{symbol}", Percent: "{n}%",
+		Months: [12]string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca",
+			"lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+		ShortDate: "02.01.2006", LongDate: "{day} {month} {year}", Time: "15:04",
+		Now: "teraz", Past: "{time} temu", Future: "za {time}",
+		Units: map[string]map[string]string{
+			"second": oneFewMany("{count} sekundę", "{count} sekundy", "{count} sekund"),
+			"minute": oneFewMany("{count} minutę", "{count} minuty", "{count} minut"),
+			"hour":   oneFewMany("{count} godzinę", "{count} godziny", "{count} godzin"),
+			"day":    oneFewMany("{count} dzień", "{count} dni", "{count} dni"),
+			"week":   oneFewMany("{count} tydzień", "{count} tygodnie", "{count} tygodni"),
+			"month":  oneFewMany("{count} miesiąc", "{count} miesiące", "{count} miesięcy"),
+			"year":   oneFewMany("{count} rok", "{count} lata", "{count} lat"),
+		},
+	}
+
+	Formats["ja"] = &Format{
+		Decimal: ".", Group: ",", Currency: "{symbol}{n}", Percent: "{n}%",
+		Months: [12]string{"1月", "2月", "3月", "4月", "5月", "6月",
+			"7月", "8月", "9月", "10月", "11月", "12月"},
+		ShortDate: "2006/01/02", LongDate: "{year}年{month}{day}日", Time: "15:04",
+		Now: "今", Past: "{time}前", Future: "{time}後",
+		Units: map[string]map[string]string{
+			"second": other("{count} 秒"),
+			"minute": other("{count} 分"),
+			"hour":   other("{count} 時間"),
+			"day":    other("{count} 日"),
+			"week":   other("{count} 週間"),
+			"month":  other("{count} か月"),
+			"year":   other("{count} 年"),
+		},
+	}
+
+	Formats["zh"] = &Format{
+		Decimal: ".", Group: ",", Currency: "{symbol}{n}", Percent: "{n}%",
+		Months: [12]string{"1月", "2月", "3月", "4月", "5月", "6月",
+			"7月", "8月", "9月", "10月", "11月", "12月"},
+		ShortDate: "2006/1/2", LongDate: "{year}年{month}{day}日", Time: "15:04",
+		Now: "现在", Past: "{time}前", Future: "{time}后",
+		Units: map[string]map[string]string{
+			"second": other("{count}秒钟"),
+			"minute": other("{count}分钟"),
+			"hour":   other("{count}小时"),
+			"day":    other("{count}天"),
+			"week":   other("{count}周"),
+			"month":  other("{count}个月"),
+			"year":   other("{count}年"),
+		},
+	}
+}
+
+// FormatOf finds the format of locale, then of its parents, then "en"
+func FormatOf(locale string) *Format {
+	for tag := strings.ReplaceAll(locale, "_", "-"); tag != ""; {
+		if f, ok := Formats[tag]; ok {
+			return f
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	if f, ok := Formats[baseLanguage(locale)]; ok {
+		return f
+	}
+	return Formats["en"]
+}
+
+/*
+ * FormatNumber writes v with the locale's separators and decimals digits
+ * after the point; a negative decimals writes as many as v needs.
+ *
+ *	FormatNumber("de", 1234.5, 2) // "1.234,50"
+ */
+func FormatNumber(locale string, v float64, decimals int) string {
+	return FormatOf(locale).number(v, decimals)
+}
+
+func (f *Format) number(v float64, decimals int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	digits := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	whole, frac, _ := strings.Cut(digits, ".")
+
+	var s strings.Builder
+	if v < 0 && strings.Trim(digits, "0.") != "" {
+		s.WriteString("-")
+	}
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			s.WriteString(f.Group)
+		}
+		s.WriteRune(d)
+	}
+	if frac != "" {
+		s.WriteString(f.Decimal)
+		s.WriteString(frac)
+	}
+	return s.String()
+}
+
+/*
+ * FormatCurrency writes amount in the ISO 4217 currency, with the
+ * currency's minor units:
+ *
+ *	FormatCurrency("fr", 1234.5, "EUR") // "1 234,50 €"
+ */
+func FormatCurrency(locale string, amount float64, currency string) string {
+	return formatCurrency(locale, amount, currency, -1)
+}
+
+// formatCurrency writes decimals digits, the currency's minor units when
+// negative
+func formatCurrency(locale string, amount float64, currency string, decimals int) string {
+	f := FormatOf(locale)
+
+	if decimals < 0 {
+		decimals = 2
+		if wholeCurrencies[currency] {
+			decimals = 0
+		}
+	}
+	symbol, ok := CurrencySymbols[currency]
+	if !ok {
+		symbol = currency
+	}
+
+	n := f.number(amount, decimals)
+	sign := ""
+	if strings.HasPrefix(n, "-") {
+		sign, n = "-", n[1:]
+	}
+	return sign + strings.NewReplacer("{symbol}", symbol, "{n}", n).Replace(f.Currency)
+}
+
+/*
+ * FormatPercent writes a ratio as a percentage:
+ *
+ *	FormatPercent("en", 0.256, 1) // "25.6%"
+ */
+func FormatPercent(locale string, ratio float64, decimals int) string {
+	f := FormatOf(locale)
+	return strings.ReplaceAll(f.Percent, "{n}", f.number(ratio*100, decimals))
+}
+
+// DateStyle picks how much of a time FormatDate writes
+type DateStyle int
+
+const (
+	// "January 2, 2006"
+	DateLong DateStyle = iota
+	// "1/2/2006"
+	DateShort
+	// "January 2, 2006 3:04 PM"
+	DateTimeLong
+	// "1/2/2006 3:04 PM"
+	DateTimeShort
+	// "3:04 PM"
+	TimeOfDay
+)
+
+// FormatDate writes t, in its own location, in the locale's style
+func FormatDate(locale string, t time.Time, style DateStyle) string {
+	f := FormatOf(locale)
+	switch style {
+	case DateShort:
+		return t.Format(f.ShortDate)
+	case DateTimeLong:
+		return f.longDate(t) + " " + t.Format(f.Time)
+	case DateTimeShort:
+		return t.Format(f.ShortDate) + " " + t.Format(f.Time)
+	case TimeOfDay:
+		return t.Format(f.Time)
+	}
+	return f.longDate(t)
+}
+
+func (f *Format) longDate(t time.Time) string {
+	return strings.NewReplacer(
+		"{day}", strconv.Itoa(t.Day()),
+		"{month}", f.Months[t.Month()-1],
+		"{year}", strconv.Itoa(t.Year()),
+	).Replace(f.LongDate)
+}
+
+/*
+ * FormatRelative writes how long before or after now t is, in the largest
+ * whole unit: "3 minutes ago", "in 2 days". Months and years are counted
+ * on the calendar, in now's location, so March 15 is a month after
+ * February 15.
+ */
+func FormatRelative(locale string, t, now time.Time) string {
+	f := FormatOf(locale)
+	units := f.Units
+	if units == nil {
+		units = Formats["en"].Units
+	}
+
+	from, to := now, t.In(now.Location())
+	pattern := f.Future
+	if to.Before(from) {
+		from, to, pattern = to, from, f.Past
+	}
+
+	unit, n := relativeUnit(from, to)
+	if n == 0 {
+		return f.Now
+	}
+
+	forms := units[unit]
+	form, ok := forms[Plural(locale, n)]
+	if !ok {
+		form = forms[Other]
+	}
+	return strings.ReplaceAll(pattern, "{time}", strings.ReplaceAll(form, "{count}", strconv.Itoa(n)))
+}
+
+// relativeUnit picks the largest unit the time from from to to holds at
+// least once
+func relativeUnit(from, to time.Time) (string, int) {
+	const day = 24 * time.Hour
+	d := to.Sub(from)
+	switch {
+	case d < time.Minute:
+		return "second", int(d / time.Second)
+	case d < time.Hour:
+		return "minute", int(d / time.Minute)
+	case d < day:
+		return "hour", int(d / time.Hour)
+	case d < 7*day:
+		return "day", int(d / day)
+	}
+
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if months > 0 && from.AddDate(0, months, 0).After(to) {
+		months--
+	}
+	switch {
+	case months >= 12:
+		return "year", months / 12
+	case months >= 1:
+		return "month", months
+	}
+	return "week", int(d / (7 * day))
+}
@@ -0,0 +1,220 @@
+package i18n
+
+import (
+	"context"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/httpcache"
+	"github.com/bitpartio/Mx/render"
+	"github.com/bitpartio/Mx/utils"
+)
+
+// Dir is the direction of text in locale
+func Dir(locale string) func() elements.DirOption {
+	if RTL(locale) {
+		return elements.GlobalOptions.Dir.Rtl
+	}
+	return elements.GlobalOptions.Dir.Ltr
+}
+
+/*
+ * HTML builds the root element with lang and dir set from the render
+ * context's locale, unless props already set them. The locale comes from
+ * the request, so only the locale Negotiate matches it to among
+ * KnownLocales is written; one matching none is left out:
+ *
+ *	<html lang="ar" dir="rtl">
+ */
+func HTML(ctx context.Context, props elements.HTMLProps) string {
+	return rootElement(Negotiate(render.Locale(ctx), KnownLocales(), ""), props)
+}
+
+// HTML is the package HTML matching the locale among the bundle's
+// Locales, so regional catalogs such as "ar-EG" keep their region
+func (b *Bundle) HTML(ctx context.Context, props elements.HTMLProps) string {
+	return rootElement(Negotiate(render.Locale(ctx), b.Locales(), ""), props)
+}
+
+func rootElement(locale string, props elements.HTMLProps) string {
+	if locale == "" {
+		return elements.HTML(props)
+	}
+
+	if props.Lang == "" && props.GlobalProps.Lang == "" {
+		props.Lang = locale
+	}
+	if props.Dir == nil {
+		props.Dir = Dir(locale)
+	}
+	return elements.HTML(props)
+}
+
+// DefaultTimeURL is where TimeHandler is expected when TimeProps.RefreshURL
+// is empty
+const DefaultTimeURL = "/_mx/i18n/time"
+
+/*
+ * TimeProps
+ */
+type TimeProps struct {
+	elements.GlobalProps
+
+	Time  time.Time
+	Style DateStyle
+	// Write how long ago or until Time it is, with the full date as title
+	Relative bool
+	// hx-trigger refreshing a relative time, e.g. "every 60s"
+	Refresh string
+	// Where TimeHandler is mounted, DefaultTimeURL when empty
+	RefreshURL string
+}
+
+/*
+ * Time builds a <time> element reading Time in the render context's
+ * locale, with its machine-readable datetime:
+ *
+ *	<time datetime="2026-10-19T09:30:00Z">October 19, 2026</time>
+ *	<time datetime="2026-10-19T09:30:00Z" title="October 19, 2026 9:30 AM"
+ *		hx-get="/_mx/i18n/time?t=2026-10-19T09%3A30%3A00Z" hx-trigger="every 60s"
+ *		hx-swap="innerHTML">3 minutes ago</time>
+ */
+func Time(ctx context.Context, props TimeProps) string {
+	locale := localeOf(ctx)
+	global := props.GlobalProps
+
+	text := FormatDate(locale, props.Time, props.Style)
+	if props.Relative {
+		if global.Title == "" {
+			global.Title = html.EscapeString(FormatDate(locale, props.Time, DateTimeLong))
+		}
+		text = FormatRelative(locale, props.Time, time.Now())
+
+		if props.Refresh != "" {
+			u := props.RefreshURL
+			if u == "" {
+				u = DefaultTimeURL
+			}
+			u += "?" + url.Values{"t": {props.Time.Format(time.RFC3339Nano)}}.Encode()
+
+			global.Htmx = utils.With(global.Htmx, "get", html.EscapeString(u))
+			global.Htmx = utils.With(global.Htmx, "trigger", html.EscapeString(props.Refresh))
+			global.Htmx = utils.With(global.Htmx, "swap", "innerHTML")
+		}
+	}
+
+	return elements.Time(elements.TimeProps{
+		GlobalProps: global,
+		Datetime:    props.Time,
+		InnerHTML:   html.EscapeString(text),
+	})
+}
+
+/*
+ * TimeHandler answers the refreshes of relative times with their text in
+ * the request's locale. Mount it at DefaultTimeURL inside
+ * render.Middleware.
+ */
+func TimeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, err := time.Parse(time.RFC3339Nano, r.URL.Query().Get("t"))
+		if err != nil {
+			http.Error(w, "invalid time", http.StatusBadRequest)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Content-Type", "text/html; charset=utf-8")
+		// The text depends on the locale, which Bundle.Locale may take
+		// from the cookie, and goes stale by the minute
+		httpcache.AddVary(h, "Accept-Language")
+		httpcache.AddVary(h, "Cookie")
+		h.Set("Cache-Control", "no-store")
+		io.WriteString(w, html.EscapeString(FormatRelative(localeOf(r.Context()), t, time.Now())))
+	})
+}
+
+/*
+ * NumberProps
+ */
+type NumberProps struct {
+	elements.GlobalProps
+
+	Value float64
+	// Digits after the decimal point: when nil, as many as Value needs,
+	// the currency's minor units, or none for a percentage
+	Decimals *int
+	// ISO 4217 code writing Value as money, e.g. "EUR"
+	Currency string
+	// Write Value, a ratio, as a percentage
+	Percent bool
+}
+
+/*
+ * Number builds a <data> element reading Value in the render context's
+ * locale, keeping the raw value:
+ *
+ *	<data value="1234.5">1.234,50 €</data>
+ */
+func Number(ctx context.Context, props NumberProps) string {
+	locale := localeOf(ctx)
+
+	var text string
+	switch {
+	case props.Currency != "":
+		text = formatCurrency(locale, props.Value, props.Currency, decimals(props.Decimals, -1))
+	case props.Percent:
+		text = FormatPercent(locale, props.Value, decimals(props.Decimals, 0))
+	default:
+		text = FormatNumber(locale, props.Value, decimals(props.Decimals, -1))
+	}
+
+	return elements.Data(elements.DataProps{
+		GlobalProps: props.GlobalProps,
+		Value:       strconv.FormatFloat(props.Value, 'f', -1, 64),
+		InnerHTML:   html.EscapeString(text),
+	})
+}
+
+func decimals(d *int, fallback int) int {
+	if d == nil {
+		return fallback
+	}
+	return *d
+}
+
+/*
+ * Meter builds a <meter> element whose fallback content, when props has
+ * none, is the value's share of the range as a localized percentage
+ */
+func Meter(ctx context.Context, props elements.MeterProps) string {
+	v, err := strconv.ParseFloat(props.Value, 64)
+	if props.InnerHTML != "" || err != nil {
+		return elements.Meter(props)
+	}
+
+	// The range defaults to 0 to 1, as in browsers
+	low, high := 0.0, 1.0
+	if props.Min != nil {
+		low = *props.Min
+	}
+	if props.Max != nil {
+		high = *props.Max
+	}
+	if high > low {
+		props.InnerHTML = html.EscapeString(FormatPercent(localeOf(ctx), (v-low)/(high-low), 0))
+	}
+	return elements.Meter(props)
+}
+
+func localeOf(ctx context.Context) string {
+	if locale := render.Locale(ctx); locale != "" {
+		return locale
+	}
+	return "en"
+}
@@ -0,0 +1,331 @@
+/*
+ * Package i18n translates Mx pages: message catalogs loaded from JSON,
+ * CLDR plural rules, escaped interpolation, Accept-Language negotiation,
+ * the lang and dir attributes of the document, and localized numbers,
+ * currencies, dates and relative times.
+ *
+ * A catalog is a JSON file per locale, named after it ("fr.json",
+ * "pt-BR.json"). Nested objects are flattened with dots, and objects keyed
+ * by plural categories are plural messages:
+ *
+ *	{
+ *		"greeting": "Bonjour, {name} !",
+ *		"cart": {
+ *			"items": {"one": "{count} article", "other": "{count} articles"}
+ *		}
+ *	}
+ *
+ * Wire it with the bundle's Middleware, which carries the negotiated
+ * locale through the render package:
+ *
+ *	msgs, err := i18n.LoadFS(os.DirFS("locales"), "en")
+ *	handler = msgs.Middleware(handler, render.Options{})
+ *
+ *	msgs.T(ctx, "cart.items", i18n.Args{"count": 3})
+ */
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bitpartio/Mx/httpcache"
+	"github.com/bitpartio/Mx/render"
+)
+
+/*
+ * A message: plain text, or forms by plural category. Messages may hold
+ * markup; what Args interpolates is escaped.
+ */
+type Message struct {
+	Text   string
+	Plural map[string]string
+}
+
+/*
+ * Args fill a message's {name} placeholders. "count" also picks the plural
+ * form.
+ */
+type Args map[string]interface{}
+
+// Raw is an argument interpolated without escaping, such as a link built
+// with the elements package
+type Raw string
+
+/*
+ * Bundle holds the catalogs of every locale. It is safe for concurrent
+ * use.
+ */
+type Bundle struct {
+	fallback string
+
+	mu       sync.RWMutex
+	catalogs map[string]map[string]Message
+}
+
+// NewBundle starts an empty bundle falling back to the fallback locale
+func NewBundle(fallback string) *Bundle {
+	return &Bundle{fallback: fallback, catalogs: map[string]map[string]Message{}}
+}
+
+/*
+ * LoadFS loads every "<locale>.json" file at the root of fsys into a new
+ * bundle
+ */
+func LoadFS(fsys fs.FS, fallback string) (*Bundle, error) {
+	b := NewBundle(fallback)
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.Load(strings.TrimSuffix(path.Base(name), ".json"), data); err != nil {
+			return nil, fmt.Errorf("i18n: %s: %w", name, err)
+		}
+	}
+	return b, nil
+}
+
+// Load adds a JSON catalog for locale, replacing messages with the same key
+func (b *Bundle) Load(locale string, data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	msgs := map[string]Message{}
+	if err := flatten("", raw, msgs); err != nil {
+		return err
+	}
+	b.Add(locale, msgs)
+	return nil
+}
+
+func flatten(prefix string, raw map[string]interface{}, out map[string]Message) error {
+	for key, v := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := v.(type) {
+		case string:
+			out[key] = Message{Text: v}
+		case map[string]interface{}:
+			if forms, ok := pluralForms(v); ok {
+				out[key] = Message{Plural: forms}
+				continue
+			}
+			if err := flatten(key, v, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q is neither text nor an object", key)
+		}
+	}
+	return nil
+}
+
+// pluralForms reads an object keyed only by plural categories, with "other"
+func pluralForms(v map[string]interface{}) (map[string]string, bool) {
+	if _, ok := v[Other]; !ok {
+		return nil, false
+	}
+	forms := make(map[string]string, len(v))
+	for category, form := range v {
+		s, ok := form.(string)
+		if !ok || !categories[category] {
+			return nil, false
+		}
+		forms[category] = s
+	}
+	return forms, true
+}
+
+// Add adds messages for locale, replacing those with the same key
+func (b *Bundle) Add(locale string, msgs map[string]Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.catalogs[locale]
+	if c == nil {
+		c = map[string]Message{}
+		b.catalogs[locale] = c
+	}
+	for key, m := range msgs {
+		c[key] = m
+	}
+}
+
+// Locales lists the locales with a catalog, sorted
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	locales := make([]string, 0, len(b.catalogs))
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+/*
+ * Locale picks the locale of a request: a supported "lang" query
+ * parameter or cookie, so users can override their browser, else the
+ * Accept-Language negotiation. It fits render.Options.Locale, but the
+ * response then varies by the cookie and Accept-Language; Middleware says
+ * so in Vary.
+ */
+func (b *Bundle) Locale(r *http.Request) string {
+	locales := b.Locales()
+	for _, explicit := range []string{r.URL.Query().Get("lang"), cookie(r, "lang")} {
+		if explicit == "" {
+			continue
+		}
+		if locale := Negotiate(explicit, locales, ""); locale != "" {
+			return locale
+		}
+	}
+	return Negotiate(r.Header.Get("Accept-Language"), locales, b.fallback)
+}
+
+/*
+ * Middleware is render.Middleware picking the locale with Locale. The
+ * page depends on the lang cookie and Accept-Language, so it adds both to
+ * Vary, keeping shared caches from serving one visitor's language to
+ * another. The lang query parameter is part of the URL already.
+ */
+func (b *Bundle) Middleware(next http.Handler, opts render.Options) http.Handler {
+	opts.Locale = b.Locale
+	h := render.Middleware(next, opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpcache.AddVary(w.Header(), "Accept-Language")
+		httpcache.AddVary(w.Header(), "Cookie")
+		h.ServeHTTP(w, r)
+	})
+}
+
+func cookie(r *http.Request, name string) string {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+/*
+ * lookup finds key for locale, then for its parents ("pt-BR", "pt") and
+ * the fallback locale
+ */
+func (b *Bundle) lookup(locale, key string) (Message, string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for tag := locale; tag != ""; {
+		if m, ok := b.catalogs[tag][key]; ok {
+			return m, tag, true
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	if m, ok := b.catalogs[b.fallback][key]; ok {
+		return m, b.fallback, true
+	}
+	return Message{}, "", false
+}
+
+/*
+ * Translate renders the message key in locale with args, as HTML. Missing
+ * messages render their escaped key, so they show up in review.
+ */
+func (b *Bundle) Translate(locale, key string, args Args) string {
+	m, found, ok := b.lookup(locale, key)
+	if !ok {
+		return html.EscapeString(key)
+	}
+
+	text := m.Text
+	if m.Plural != nil {
+		category := Other
+		if n, ok := count(args); ok {
+			// Plural rules follow the catalog the forms came from
+			category = Plural(found, n)
+		}
+		text, ok = m.Plural[category]
+		if !ok {
+			text = m.Plural[Other]
+		}
+	}
+	return interpolate(text, args)
+}
+
+// T translates key into the locale of the render context
+func (b *Bundle) T(ctx context.Context, key string, args Args) string {
+	locale := render.Locale(ctx)
+	if locale == "" {
+		locale = b.fallback
+	}
+	return b.Translate(locale, key, args)
+}
+
+func count(args Args) (int, bool) {
+	switch n := args["count"].(type) {
+	case int:
+		return n, true
+	case int8:
+		return int(n), true
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint:
+		return int(n), true
+	case uint8:
+		return int(n), true
+	case uint16:
+		return int(n), true
+	case uint32:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+var placeholder = regexp.MustCompile(`\{([A-Za-z0-9_.]+)\}`)
+
+// interpolate fills placeholders, escaping all but Raw arguments; unknown
+// placeholders are left as written
+func interpolate(text string, args Args) string {
+	if len(args) == 0 {
+		return text
+	}
+	return placeholder.ReplaceAllStringFunc(text, func(p string) string {
+		v, ok := args[p[1:len(p)-1]]
+		if !ok {
+			return p
+		}
+		if raw, ok := v.(Raw); ok {
+			return string(raw)
+		}
+		return html.EscapeString(fmt.Sprint(v))
+	})
+}
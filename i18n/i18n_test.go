@@ -0,0 +1,238 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/render"
+)
+
+func TestTranslate(t *testing.T) {
+	b, err := LoadFS(fstest.MapFS{
+		"en.json": {Data: []byte(`{"hi": "Hello, {name}", "cart": {"items": {"one": "{count} item", "other": "{count} items"}}}`)},
+		"ru.json": {Data: []byte(`{"cart": {"items": {"one": "{count} товар", "few": "{count} товара", "many": "{count} товаров", "other": "{count} товара"}}}`)},
+	}, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		locale, key string
+		args        Args
+		want        string
+	}{
+		{"en", "hi", Args{"name": "<b>"}, "Hello, &lt;b&gt;"},
+		{"en", "hi", Args{"name": Raw("<b>")}, "Hello, <b>"},
+		{"en-GB", "cart.items", Args{"count": 1}, "1 item"},
+		{"ru", "cart.items", Args{"count": 3}, "3 товара"},
+		{"ru", "cart.items", Args{"count": 11}, "11 товаров"},
+		{"ru", "hi", Args{"name": "Ada"}, "Hello, Ada"},
+		{"en", "missing<", nil, "missing&lt;"},
+	} {
+		if got := b.Translate(c.locale, c.key, c.args); got != c.want {
+			t.Errorf("%s %s: got %q, want %q", c.locale, c.key, got, c.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	b, err := LoadFS(fstest.MapFS{
+		"en.json": {Data: []byte(`{"hi": "Hello"}`)},
+		"fr.json": {Data: []byte(`{"hi": "Bonjour"}`)},
+	}, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := b.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		w.Write([]byte(b.HTML(ctx, elements.HTMLProps{InnerHTML: b.T(ctx, "hi", nil)})))
+	}), render.Options{})
+
+	for _, c := range []struct {
+		name   string
+		header http.Header
+		want   string
+	}{
+		{"default", nil, `lang="en"`},
+		{"browser", http.Header{"Accept-Language": {"fr-CA, en;q=0.5"}}, `lang="fr"`},
+		{"cookie", http.Header{"Accept-Language": {"en"}, "Cookie": {"lang=fr"}}, `lang="fr"`},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		for k, v := range c.header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if !strings.Contains(rec.Body.String(), c.want) {
+			t.Errorf("%s: got %s", c.name, rec.Body.String())
+		}
+		if got := rec.Header().Values("Vary"); strings.Join(got, ", ") != "Accept-Language, Cookie" {
+			t.Errorf("%s: Vary %q", c.name, got)
+		}
+	}
+
+	// Time refreshes behind it list each field once
+	rec := httptest.NewRecorder()
+	b.Middleware(TimeHandler(), render.Options{}).ServeHTTP(rec, httptest.NewRequest("GET", DefaultTimeURL+"?t=2026-10-19T09:30:00Z", nil))
+	if got := rec.Header().Values("Vary"); strings.Join(got, ", ") != "Accept-Language, Cookie" {
+		t.Errorf("time: Vary %q", got)
+	}
+}
+
+func TestPlural(t *testing.T) {
+	for n, want := range map[int]string{0: Zero, 1: One, 2: Two, 5: Few, 11: Many, 100: Other} {
+		if got := Plural("ar", n); got != want {
+			t.Errorf("ar %d: got %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	available := []string{"en", "fr", "pt-BR"}
+	for header, want := range map[string]string{
+		"en-US,en;q=0.9":  "en",
+		"de, fr;q=0.5":    "fr",
+		"pt":              "pt-BR",
+		"de":              "en",
+		"fr;q=0.1, pt-PT": "pt-BR",
+	} {
+		if got := Negotiate(header, available, "en"); got != want {
+			t.Errorf("%q: got %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	ctx := render.WithLocale(context.Background(), "ar-EG")
+	got := HTML(ctx, elements.HTMLProps{})
+	if !strings.Contains(got, `lang="ar"`) || !strings.Contains(got, `dir="rtl"`) {
+		t.Fatalf("got %s", got)
+	}
+
+	b := NewBundle("en")
+	b.Add("ar-EG", map[string]Message{})
+	if got := b.HTML(ctx, elements.HTMLProps{}); !strings.Contains(got, `lang="ar-EG"`) {
+		t.Fatalf("bundle gave %s", got)
+	}
+
+	// Locales come from requests, so anything not known is never written
+	for _, hostile := range []string{`en"><script>alert(1)</script>`, `xx" onload="alert(1)`} {
+		ctx := render.WithLocale(context.Background(), hostile)
+		got := HTML(ctx, elements.HTMLProps{}) + b.HTML(ctx, elements.HTMLProps{})
+		if strings.Contains(got, "<script") || strings.Contains(got, "onload") {
+			t.Errorf("%q: got %s", hostile, got)
+		}
+	}
+	if RTL("en") || !RTL("he-IL") || !RTL("az-Arab") || RTL("ar-Latn") {
+		t.Fatal("wrong direction")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, c := range []struct{ got, want string }{
+		{FormatNumber("en-US", -1234567.891, 2), "-1,234,567.89"},
+		{FormatNumber("de", 1234.5, -1), "1.234,5"},
+		{FormatCurrency("fr-FR", 1234.5, "EUR"), "1\u202f234,50\u00a0€"},
+		{FormatCurrency("en", -3, "USD"), "-$3.00"},
+		{FormatCurrency("ja", 1234.5, "JPY"), "¥1,234"},
+		{FormatPercent("de-CH", 0.256, 1), "25.6%"},
+		{FormatDate("es", time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC), DateLong), "19 de octubre de 2026"},
+		{FormatDate("en", time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC), DateTimeShort), "10/19/2026 9:30 AM"},
+	} {
+		if c.got != c.want {
+			t.Errorf("got %q, want %q", c.got, c.want)
+		}
+	}
+}
+
+func TestFormatRelative(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		locale string
+		d      time.Duration
+		want   string
+	}{
+		{"en", -3 * time.Minute, "3 minutes ago"},
+		{"en", 26 * time.Hour, "in 1 day"},
+		{"ru", -5 * time.Hour, "5 часов назад"},
+		{"de", -2 * 24 * time.Hour, "vor 2 Tagen"},
+		{"fr", 0, "maintenant"},
+	} {
+		if got := FormatRelative(c.locale, now.Add(c.d), now); got != c.want {
+			t.Errorf("%s %v: got %q, want %q", c.locale, c.d, got, c.want)
+		}
+	}
+}
+
+func TestFormatRelativeCalendar(t *testing.T) {
+	for _, c := range []struct {
+		t, now time.Time
+		want   string
+	}{
+		// February is short: a calendar month back is only 28 days
+		{date(2026, 2, 15, 12), date(2026, 3, 15, 12), "1 month ago"},
+		{date(2026, 2, 15, 13), date(2026, 3, 15, 12), "3 weeks ago"},
+		{date(2026, 9, 19, 12), date(2026, 10, 19, 12), "1 month ago"},
+		{date(2026, 9, 20, 12), date(2026, 10, 19, 12), "4 weeks ago"},
+		// and a 31 day month needs all 31
+		{date(2026, 1, 1, 12), date(2026, 1, 31, 12), "4 weeks ago"},
+		{date(2026, 1, 1, 12), date(2026, 2, 1, 12), "1 month ago"},
+		{date(2026, 12, 19, 12), date(2026, 10, 19, 12), "in 2 months"},
+		// A leap year takes 366 days
+		{date(2024, 2, 28, 12), date(2025, 2, 28, 12), "1 year ago"},
+		{date(2024, 2, 29, 12), date(2025, 2, 28, 12), "11 months ago"},
+		{date(2023, 3, 1, 12), date(2024, 2, 29, 12), "11 months ago"},
+		{date(2023, 3, 1, 12), date(2024, 3, 1, 12), "1 year ago"},
+		{date(2028, 10, 20, 12), date(2026, 10, 19, 12), "in 2 years"},
+	} {
+		if got := FormatRelative("en", c.t, c.now); got != c.want {
+			t.Errorf("%v from %v: got %q, want %q", c.t, c.now, got, c.want)
+		}
+	}
+}
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestTimeHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	TimeHandler().ServeHTTP(rec, httptest.NewRequest("GET", DefaultTimeURL+"?t="+url.QueryEscape(time.Now().Add(-3*time.Minute).Format(time.RFC3339)), nil))
+
+	if rec.Body.String() != "3 minutes ago" {
+		t.Errorf("body %q", rec.Body.String())
+	}
+	if got := rec.Header().Values("Vary"); strings.Join(got, ", ") != "Accept-Language, Cookie" {
+		t.Errorf("Vary %q", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control %q", got)
+	}
+}
+
+func TestElements(t *testing.T) {
+	ctx := render.WithLocale(context.Background(), "de")
+
+	got := Number(ctx, NumberProps{Value: 1234.5, Currency: "EUR"})
+	if !strings.Contains(got, `value="1234.5"`) || !strings.Contains(got, "1.234,50\u00a0€") {
+		t.Fatalf("got %s", got)
+	}
+
+	got = Time(ctx, TimeProps{Time: time.Now().Add(-time.Hour), Relative: true, Refresh: "every 60s"})
+	if !strings.Contains(got, `datetime="`) || !strings.Contains(got, "vor 1 Stunde") || !strings.Contains(got, `hx-trigger="every 60s"`) {
+		t.Fatalf("got %s", got)
+	}
+
+	max := 10.0
+	if got := Meter(ctx, elements.MeterProps{Value: "4", Max: &max}); !strings.Contains(got, ">40\u00a0%</meter>") {
+		t.Fatalf("got %s", got)
+	}
+}
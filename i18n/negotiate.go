@@ -0,0 +1,130 @@
+package i18n
+
+// Ref: https://www.rfc-editor.org/rfc/rfc4647#section-3.4
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type preference struct {
+	tag string
+	q   float64
+}
+
+// parseAcceptLanguage lists the header's tags by descending weight
+func parseAcceptLanguage(header string) []preference {
+	var prefs []preference
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			f, err := strconv.ParseFloat(params[2:], 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if q > 0 {
+			prefs = append(prefs, preference{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+	return prefs
+}
+
+/*
+ * Negotiate picks the available locale best matching an Accept-Language
+ * header, or fallback. For each preference in order it tries an exact
+ * match, then ever shorter prefixes ("zh-Hant-TW", "zh-Hant", "zh"), then
+ * any available locale of the same language, so "en-US" gets "en" and
+ * "pt" gets "pt-BR".
+ */
+func Negotiate(header string, available []string, fallback string) string {
+	byTag := make(map[string]string, len(available))
+	byBase := map[string]string{}
+	for _, locale := range available {
+		byTag[strings.ToLower(locale)] = locale
+		if _, ok := byBase[baseLanguage(locale)]; !ok {
+			byBase[baseLanguage(locale)] = locale
+		}
+	}
+
+	for _, p := range parseAcceptLanguage(header) {
+		if p.tag == "*" {
+			return fallback
+		}
+
+		tag := strings.ToLower(strings.ReplaceAll(p.tag, "_", "-"))
+		for {
+			if locale, ok := byTag[tag]; ok {
+				return locale
+			}
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+		if locale, ok := byBase[tag]; ok {
+			return locale
+		}
+	}
+	return fallback
+}
+
+// KnownLocales lists the locales with a Format or a PluralRule, sorted
+func KnownLocales() []string {
+	seen := map[string]bool{}
+	for locale := range Formats {
+		seen[locale] = true
+	}
+	for locale := range PluralRules {
+		seen[locale] = true
+	}
+
+	locales := make([]string, 0, len(seen))
+	for locale := range seen {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// baseLanguage is the language subtag, lowercased: "pt" for "pt-BR"
+func baseLanguage(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	base, _, _ := strings.Cut(locale, "-")
+	return base
+}
+
+// Languages written right to left
+var rtl = map[string]bool{
+	"ar": true, "ckb": true, "dv": true, "fa": true, "he": true, "iw": true,
+	"ks": true, "ps": true, "sd": true, "ug": true, "ur": true, "yi": true,
+}
+
+/*
+ * RTL reports whether locale is written right to left, by its script
+ * subtag when it has one ("az-Arab") and by its language otherwise
+ */
+func RTL(locale string) bool {
+	parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	for _, sub := range parts[1:] {
+		if len(sub) == 4 {
+			switch strings.ToLower(sub) {
+			case "arab", "hebr", "thaa", "syrc", "nkoo", "adlm", "rohg":
+				return true
+			}
+			return false
+		}
+	}
+	return rtl[strings.ToLower(parts[0])]
+}
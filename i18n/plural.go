@@ -0,0 +1,165 @@
+package i18n
+
+// Ref: https://www.unicode.org/cldr/charts/latest/supplemental/language_plural_rules.html
+
+// CLDR plural categories, the keys of plural messages
+const (
+	Zero  = "zero"
+	One   = "one"
+	Two   = "two"
+	Few   = "few"
+	Many  = "many"
+	Other = "other"
+)
+
+var categories = map[string]bool{Zero: true, One: true, Two: true, Few: true, Many: true, Other: true}
+
+// PluralRule picks the category of a whole number count
+type PluralRule func(n int) string
+
+/*
+ * Cardinal plural rules of common languages, by base language. Languages
+ * missing here use the English rule; add entries to support others.
+ */
+var PluralRules = map[string]PluralRule{}
+
+func init() {
+	for _, lang := range []string{
+		"en", "de", "nl", "sv", "da", "nb", "nn", "no", "fi", "et", "it", "es", "el",
+		"hu", "tr", "bg", "ca", "eu", "gl", "af", "sw", "ur", "az", "ka", "kk", "sq",
+	} {
+		PluralRules[lang] = pluralOneOther
+	}
+	for _, lang := range []string{"ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my", "km"} {
+		PluralRules[lang] = pluralOther
+	}
+	for _, lang := range []string{"ru", "uk", "be", "sr", "hr", "bs"} {
+		PluralRules[lang] = pluralEastSlavic
+	}
+	PluralRules["fr"] = pluralZeroOne
+	PluralRules["pt"] = pluralZeroOne
+	PluralRules["hi"] = pluralZeroOne
+	PluralRules["fa"] = pluralZeroOne
+	PluralRules["pl"] = pluralPolish
+	PluralRules["cs"] = pluralCzech
+	PluralRules["sk"] = pluralCzech
+	PluralRules["ar"] = pluralArabic
+	PluralRules["he"] = pluralHebrew
+	PluralRules["lt"] = pluralLithuanian
+	PluralRules["ro"] = pluralRomanian
+}
+
+// Plural picks the category of n in locale
+func Plural(locale string, n int) string {
+	if rule, ok := PluralRules[baseLanguage(locale)]; ok {
+		return rule(n)
+	}
+	return pluralOneOther(n)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func pluralOther(n int) string {
+	return Other
+}
+
+func pluralOneOther(n int) string {
+	if abs(n) == 1 {
+		return One
+	}
+	return Other
+}
+
+// French, Portuguese: 0 and 1 are singular
+func pluralZeroOne(n int) string {
+	if abs(n) <= 1 {
+		return One
+	}
+	return Other
+}
+
+func pluralEastSlavic(n int) string {
+	n = abs(n)
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return One
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return Few
+	}
+	return Many
+}
+
+func pluralPolish(n int) string {
+	n = abs(n)
+	switch {
+	case n == 1:
+		return One
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return Few
+	}
+	return Many
+}
+
+func pluralCzech(n int) string {
+	switch n = abs(n); {
+	case n == 1:
+		return One
+	case n >= 2 && n <= 4:
+		return Few
+	}
+	return Other
+}
+
+func pluralArabic(n int) string {
+	n = abs(n)
+	switch {
+	case n == 0:
+		return Zero
+	case n == 1:
+		return One
+	case n == 2:
+		return Two
+	case n%100 >= 3 && n%100 <= 10:
+		return Few
+	case n%100 >= 11:
+		return Many
+	}
+	return Other
+}
+
+func pluralHebrew(n int) string {
+	switch abs(n) {
+	case 1:
+		return One
+	case 2:
+		return Two
+	}
+	return Other
+}
+
+func pluralLithuanian(n int) string {
+	n = abs(n)
+	switch {
+	case n%10 == 1 && (n%100 < 11 || n%100 > 19):
+		return One
+	case n%10 >= 2 && (n%100 < 11 || n%100 > 19):
+		return Few
+	}
+	return Other
+}
+
+func pluralRomanian(n int) string {
+	n = abs(n)
+	switch {
+	case n == 1:
+		return One
+	case n == 0 || (n%100 >= 2 && n%100 <= 19):
+		return Few
+	}
+	return Other
+}
@@ -0,0 +1,114 @@
+/*
+ * Package jsonld renders schema.org structured data as
+ * <script type="application/ld+json">. Nodes are either the typed builders
+ * in this package, which check the properties search engines require, or
+ * any Go value encoding/json can marshal.
+ */
+package jsonld
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/bitpartio/Mx/elements"
+)
+
+const Context = "https://schema.org"
+
+// Implemented by nodes that know their required properties
+type Validator interface {
+	Validate() error
+}
+
+// ErrNode reports a nil node, or one that does not marshal to an object
+var ErrNode = errors.New("jsonld: node must be a non-nil value marshalling to a JSON object")
+
+/*
+ * Reported when a node lacks required properties, listed by their path
+ * from the node, e.g. "offers[0].price".
+ */
+type ValidationError struct {
+	Type    string
+	Missing []string
+}
+
+func (e *ValidationError) Error() string {
+	return "jsonld: " + e.Type + " is missing " + strings.Join(e.Missing, ", ")
+}
+
+/*
+ * Script validates and marshals nodes into a JSON-LD <script> element.
+ * A single node gets an "@context"; several are combined in an "@graph".
+ * The JSON has "<", ">" and "&" escaped, so it is safe in script context
+ * whatever the data holds.
+ */
+func Script(nodes ...interface{}) (string, error) {
+	data, err := Marshal(nodes...)
+	if err != nil {
+		return "", err
+	}
+
+	return elements.Script(elements.ScriptProps{
+		Type:      elements.ScriptOptions.Type.JSONLD,
+		InnerHTML: string(data),
+	}), nil
+}
+
+// Marshal validates nodes and returns the JSON-LD document Script embeds
+func Marshal(nodes ...interface{}) ([]byte, error) {
+	for _, n := range nodes {
+		if err := Validate(n); err != nil {
+			return nil, err
+		}
+	}
+
+	objects := make([]map[string]json.RawMessage, len(nodes))
+	for i, n := range nodes {
+		b, err := json.Marshal(n)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.HasPrefix(b, []byte("{")) {
+			return nil, ErrNode
+		}
+		if err := json.Unmarshal(b, &objects[i]); err != nil {
+			return nil, err
+		}
+		delete(objects[i], "@context")
+	}
+
+	context, _ := json.Marshal(Context)
+	if len(objects) == 1 {
+		objects[0]["@context"] = context
+		return json.Marshal(objects[0])
+	}
+
+	graph, err := json.Marshal(objects)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]json.RawMessage{
+		"@context": context,
+		"@graph":   graph,
+	})
+}
+
+// Validate checks n when it is a Validator, nil otherwise. A nil node,
+// typed or not, is ErrNode.
+func Validate(n interface{}) error {
+	switch v := reflect.ValueOf(n); v.Kind() {
+	case reflect.Invalid:
+		return ErrNode
+	case reflect.Ptr, reflect.Map, reflect.Interface, reflect.Slice:
+		if v.IsNil() {
+			return ErrNode
+		}
+	}
+	if v, ok := n.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
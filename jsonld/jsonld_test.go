@@ -0,0 +1,108 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshal(t *testing.T) {
+	b, err := Marshal(Product{
+		Name:   "Mug",
+		Brand:  "Acme",
+		Offers: []Offer{{Price: "9.50", PriceCurrency: "EUR", PriceValidUntil: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"@context":"https://schema.org","@type":"Product","brand":{"@type":"Brand","name":"Acme"},"name":"Mug","offers":[{"@type":"Offer","price":"9.50","priceCurrency":"EUR","priceValidUntil":"2030-01-02"}]}`
+	if string(b) != want {
+		t.Errorf("got %s", b)
+	}
+}
+
+func TestMarshalGraph(t *testing.T) {
+	b, err := Marshal(
+		Organization{Name: "Acme"},
+		BreadcrumbList{Items: []ListItem{{Name: "Home", Item: "https://example.com/"}, {Name: "Mugs"}}},
+		map[string]interface{}{"@context": "https://example.com", "@type": "Thing"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	if strings.Count(s, "@context") != 1 || !strings.Contains(s, `"@graph":[`) {
+		t.Errorf("got %s", s)
+	}
+	if !strings.Contains(s, `"position":2`) || !strings.Contains(s, `{"@type":"Thing"}`) {
+		t.Errorf("got %s", s)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, c := range []struct {
+		node interface{}
+		want string
+	}{
+		{Product{Offers: []Offer{{Price: "1"}}}, "jsonld: Product is missing name, offers[0].priceCurrency"},
+		{Product{Name: "Mug"}, "jsonld: Product is missing offers"},
+		{Product{Name: "Mug", Review: []Review{{RatingValue: 5}}}, "jsonld: Product is missing review[0].author.name"},
+		{BreadcrumbList{Items: []ListItem{{Name: "Home"}, {}}}, "jsonld: BreadcrumbList is missing itemListElement[0].item, itemListElement[1].name"},
+		{Article{Type: "BlogPosting", Publisher: &Organization{}}, "jsonld: BlogPosting is missing headline, publisher.name"},
+		{FAQPage{Questions: []Question{{Name: "Why?"}}}, "jsonld: FAQPage is missing mainEntity[0].acceptedAnswer.text"},
+	} {
+		err := Validate(c.node)
+		var v *ValidationError
+		if !errors.As(err, &v) || err.Error() != c.want {
+			t.Errorf("got %v, want %s", err, c.want)
+		}
+		if _, err := Script(c.node); err == nil {
+			t.Errorf("%s rendered", c.want)
+		}
+	}
+
+	if err := Validate(struct{}{}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMarshalNonObjects(t *testing.T) {
+	var product *Product
+	var m map[string]interface{}
+	for name, nodes := range map[string][]interface{}{
+		"nil":           {nil},
+		"typed nil":     {product},
+		"nil map":       {m},
+		"nil in graph":  {Organization{Name: "Acme"}, nil},
+		"string":        {"Acme"},
+		"number":        {42},
+		"array":         {[]Organization{{Name: "Acme"}}},
+		"marshals null": {json.RawMessage("null")},
+	} {
+		if _, err := Script(nodes...); !errors.Is(err, ErrNode) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+
+	if err := Validate(product); err != ErrNode {
+		t.Errorf("Validate typed nil: got %v", err)
+	}
+	if _, err := Marshal(&Organization{Name: "Acme"}); err != nil {
+		t.Errorf("pointer node: %v", err)
+	}
+}
+
+func TestScript(t *testing.T) {
+	s, err := Script(FAQPage{Questions: []Question{{Name: "</script><script>alert(1)</script>", Answer: "<p>Yes & no</p>"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(s, `type="application/ld+json"`) {
+		t.Errorf("got %s", s)
+	}
+	if strings.Count(s, "<") != 2 || strings.Contains(s, "&") {
+		t.Errorf("data not escaped for script context: %s", s)
+	}
+}
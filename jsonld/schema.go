@@ -0,0 +1,405 @@
+package jsonld
+
+// Ref: https://developers.google.com/search/docs/appearance/structured-data/search-gallery
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+/*
+ * Organization
+ */
+type Organization struct {
+	Name        string
+	URL         string
+	Logo        string
+	Description string
+	Email       string
+	Telephone   string
+	// Profiles on other sites
+	SameAs []string
+}
+
+func (o Organization) MarshalJSON() ([]byte, error) {
+	n := node("Organization")
+	n.set("name", o.Name)
+	n.set("url", o.URL)
+	n.set("logo", o.Logo)
+	n.set("description", o.Description)
+	n.set("email", o.Email)
+	n.set("telephone", o.Telephone)
+	n.set("sameAs", o.SameAs)
+	return json.Marshal(n)
+}
+
+func (o Organization) Validate() error {
+	return check("Organization", o.missing(""))
+}
+
+func (o Organization) missing(path string) []string {
+	var m []string
+	if o.Name == "" {
+		m = append(m, path+"name")
+	}
+	return m
+}
+
+/*
+ * Person
+ */
+type Person struct {
+	Name string
+	URL  string
+}
+
+func (p Person) MarshalJSON() ([]byte, error) {
+	n := node("Person")
+	n.set("name", p.Name)
+	n.set("url", p.URL)
+	return json.Marshal(n)
+}
+
+func (p Person) Validate() error {
+	return check("Person", p.missing(""))
+}
+
+func (p Person) missing(path string) []string {
+	var m []string
+	if p.Name == "" {
+		m = append(m, path+"name")
+	}
+	return m
+}
+
+/*
+ * Product, which needs at least one of Offers, Review or AggregateRating
+ */
+type Product struct {
+	Name            string
+	Description     string
+	Image           []string
+	SKU             string
+	GTIN            string
+	Brand           string
+	Offers          []Offer
+	AggregateRating *AggregateRating
+	Review          []Review
+}
+
+func (p Product) MarshalJSON() ([]byte, error) {
+	n := node("Product")
+	n.set("name", p.Name)
+	n.set("description", p.Description)
+	n.set("image", p.Image)
+	n.set("sku", p.SKU)
+	n.set("gtin", p.GTIN)
+	if p.Brand != "" {
+		brand := node("Brand")
+		brand.set("name", p.Brand)
+		n.set("brand", brand)
+	}
+	n.set("offers", p.Offers)
+	n.set("aggregateRating", p.AggregateRating)
+	n.set("review", p.Review)
+	return json.Marshal(n)
+}
+
+func (p Product) Validate() error {
+	var m []string
+	if p.Name == "" {
+		m = append(m, "name")
+	}
+	if len(p.Offers) == 0 && len(p.Review) == 0 && p.AggregateRating == nil {
+		m = append(m, "offers")
+	}
+	for i, o := range p.Offers {
+		m = append(m, o.missing("offers["+strconv.Itoa(i)+"].")...)
+	}
+	if p.AggregateRating != nil {
+		m = append(m, p.AggregateRating.missing("aggregateRating.")...)
+	}
+	for i, r := range p.Review {
+		m = append(m, r.missing("review["+strconv.Itoa(i)+"].")...)
+	}
+	return check("Product", m)
+}
+
+type Offer struct {
+	// Decimal string, e.g. "19.99", so no precision is lost
+	Price string
+	// ISO 4217, e.g. "EUR"
+	PriceCurrency string
+	// schema.org ItemAvailability, e.g. "https://schema.org/InStock"
+	Availability    string
+	URL             string
+	PriceValidUntil time.Time
+}
+
+func (o Offer) MarshalJSON() ([]byte, error) {
+	n := node("Offer")
+	n.set("price", o.Price)
+	n.set("priceCurrency", o.PriceCurrency)
+	n.set("availability", o.Availability)
+	n.set("url", o.URL)
+	n.set("priceValidUntil", date(o.PriceValidUntil))
+	return json.Marshal(n)
+}
+
+func (o Offer) missing(path string) []string {
+	var m []string
+	if o.Price == "" {
+		m = append(m, path+"price")
+	}
+	if o.PriceCurrency == "" {
+		m = append(m, path+"priceCurrency")
+	}
+	return m
+}
+
+type AggregateRating struct {
+	RatingValue float64
+	ReviewCount int
+	BestRating  float64
+	WorstRating float64
+}
+
+func (a AggregateRating) MarshalJSON() ([]byte, error) {
+	n := node("AggregateRating")
+	n.set("ratingValue", a.RatingValue)
+	n.set("reviewCount", a.ReviewCount)
+	n.set("bestRating", a.BestRating)
+	n.set("worstRating", a.WorstRating)
+	return json.Marshal(n)
+}
+
+func (a AggregateRating) missing(path string) []string {
+	var m []string
+	if a.RatingValue == 0 {
+		m = append(m, path+"ratingValue")
+	}
+	if a.ReviewCount == 0 {
+		m = append(m, path+"reviewCount")
+	}
+	return m
+}
+
+type Review struct {
+	Author        Person
+	ReviewBody    string
+	RatingValue   float64
+	BestRating    float64
+	DatePublished time.Time
+}
+
+func (r Review) MarshalJSON() ([]byte, error) {
+	n := node("Review")
+	n.set("author", r.Author)
+	n.set("reviewBody", r.ReviewBody)
+	if r.RatingValue != 0 {
+		rating := node("Rating")
+		rating.set("ratingValue", r.RatingValue)
+		rating.set("bestRating", r.BestRating)
+		n.set("reviewRating", rating)
+	}
+	n.set("datePublished", date(r.DatePublished))
+	return json.Marshal(n)
+}
+
+func (r Review) missing(path string) []string {
+	return r.Author.missing(path + "author.")
+}
+
+/*
+ * BreadcrumbList, positions are assigned in order
+ */
+type BreadcrumbList struct {
+	Items []ListItem
+}
+
+type ListItem struct {
+	Name string
+	// May be left empty for the last item, the current page
+	Item string
+}
+
+func (b BreadcrumbList) MarshalJSON() ([]byte, error) {
+	items := make([]object, len(b.Items))
+	for i, item := range b.Items {
+		li := node("ListItem")
+		li.set("position", i+1)
+		li.set("name", item.Name)
+		li.set("item", item.Item)
+		items[i] = li
+	}
+
+	n := node("BreadcrumbList")
+	n.set("itemListElement", items)
+	return json.Marshal(n)
+}
+
+func (b BreadcrumbList) Validate() error {
+	var m []string
+	if len(b.Items) == 0 {
+		m = append(m, "itemListElement")
+	}
+	for i, item := range b.Items {
+		path := "itemListElement[" + strconv.Itoa(i) + "]."
+		if item.Name == "" {
+			m = append(m, path+"name")
+		}
+		if item.Item == "" && i < len(b.Items)-1 {
+			m = append(m, path+"item")
+		}
+	}
+	return check("BreadcrumbList", m)
+}
+
+/*
+ * Article. Type may be "NewsArticle" or "BlogPosting"; "Article" when
+ * empty.
+ */
+type Article struct {
+	Type          string
+	Headline      string
+	Description   string
+	Image         []string
+	Author        []Person
+	Publisher     *Organization
+	DatePublished time.Time
+	DateModified  time.Time
+	URL           string
+}
+
+func (a Article) MarshalJSON() ([]byte, error) {
+	t := a.Type
+	if t == "" {
+		t = "Article"
+	}
+
+	n := node(t)
+	n.set("headline", a.Headline)
+	n.set("description", a.Description)
+	n.set("image", a.Image)
+	n.set("author", a.Author)
+	n.set("publisher", a.Publisher)
+	n.set("datePublished", datetime(a.DatePublished))
+	n.set("dateModified", datetime(a.DateModified))
+	n.set("url", a.URL)
+	return json.Marshal(n)
+}
+
+func (a Article) Validate() error {
+	var m []string
+	if a.Headline == "" {
+		m = append(m, "headline")
+	}
+	for i, p := range a.Author {
+		m = append(m, p.missing("author["+strconv.Itoa(i)+"].")...)
+	}
+	if a.Publisher != nil {
+		m = append(m, a.Publisher.missing("publisher.")...)
+	}
+
+	t := a.Type
+	if t == "" {
+		t = "Article"
+	}
+	return check(t, m)
+}
+
+/*
+ * FAQPage
+ */
+type FAQPage struct {
+	Questions []Question
+}
+
+type Question struct {
+	Name string
+	// May contain a limited set of HTML, e.g. <p>, <a> and <ul>
+	Answer string
+}
+
+func (f FAQPage) MarshalJSON() ([]byte, error) {
+	questions := make([]object, len(f.Questions))
+	for i, q := range f.Questions {
+		answer := node("Answer")
+		answer.set("text", q.Answer)
+
+		question := node("Question")
+		question.set("name", q.Name)
+		question.set("acceptedAnswer", answer)
+		questions[i] = question
+	}
+
+	n := node("FAQPage")
+	n.set("mainEntity", questions)
+	return json.Marshal(n)
+}
+
+func (f FAQPage) Validate() error {
+	var m []string
+	if len(f.Questions) == 0 {
+		m = append(m, "mainEntity")
+	}
+	for i, q := range f.Questions {
+		path := "mainEntity[" + strconv.Itoa(i) + "]."
+		if q.Name == "" {
+			m = append(m, path+"name")
+		}
+		if q.Answer == "" {
+			m = append(m, path+"acceptedAnswer.text")
+		}
+	}
+	return check("FAQPage", m)
+}
+
+/*
+ * Helpers
+ */
+type object map[string]interface{}
+
+func node(t string) object {
+	return object{"@type": t}
+}
+
+// set leaves out empty values, so optional properties need no omitempty
+// bookkeeping
+func (o object) set(key string, v interface{}) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case !rv.IsValid():
+		return
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map:
+		if rv.Len() == 0 {
+			return
+		}
+	case rv.IsZero():
+		return
+	}
+	o[key] = v
+}
+
+func check(t string, missing []string) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	return &ValidationError{Type: t, Missing: missing}
+}
+
+func date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func datetime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
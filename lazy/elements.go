@@ -0,0 +1,148 @@
+package lazy
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * SectionProps
+ */
+type SectionProps struct {
+	// On the placeholder <div>
+	elements.GlobalProps
+
+	// Names the fragment's URL, e.g. "revenue"
+	Key string
+	// Registered under Key the first time it renders; may be nil when
+	// Handle registered Key at startup
+	Fragment Fragment
+	// Shown until the fragment arrives, such as Skeleton
+	Placeholder string
+	// Added to the fragment URL, see Query
+	Params url.Values
+	// hx-trigger, "load" when empty, e.g. "load delay:1s" or "intersect once"
+	Trigger string
+}
+
+/*
+ * Section renders a placeholder replaced by the fragment once the page has
+ * loaded:
+ *
+ *	<div hx-get="/_mx/lazy/revenue?range=30d" hx-trigger="load"
+ *		hx-swap="outerHTML" aria-busy="true">placeholder</div>
+ */
+func (reg *Registry) Section(props SectionProps) string {
+	trigger := props.Trigger
+	if trigger == "" {
+		trigger = "load"
+	}
+
+	global := props.GlobalProps
+	global.Htmx = hx(global.Htmx, fragmentURL(reg.Register(props.Key, props.Fragment), props.Params), trigger)
+	global.Aria = utils.With(global.Aria, "busy", "true")
+
+	return elements.Div(elements.DivProps{GlobalProps: global, InnerHTML: props.Placeholder})
+}
+
+// Section renders a section of Default
+func Section(props SectionProps) string {
+	return Default.Section(props)
+}
+
+// Lazy renders fn, registered under key, as a Section of Default, in
+// place of placeholder until loaded
+func Lazy(key string, fn Fragment, placeholder string) string {
+	return Default.Section(SectionProps{Key: key, Fragment: fn, Placeholder: placeholder})
+}
+
+/*
+ * SentinelProps
+ */
+type SentinelProps struct {
+	// On the sentinel element
+	elements.GlobalProps
+
+	// Names the fragment's URL, e.g. "orders"
+	Key string
+	// Renders the next items followed by the next sentinel, or no sentinel
+	// after the last items. Registered under Key the first time it
+	// renders; may be nil when Handle registered Key at startup.
+	Fragment Fragment
+	// Element of the sentinel, fitting the list it ends: "tr", "li" or,
+	// when empty, "div"
+	Tag string
+	// Columns a "tr" sentinel spans
+	Colspan int
+	// Shown while loading, e.g. "Loading…"
+	Placeholder string
+	// Added to the fragment URL, e.g. the next page or cursor
+	Params url.Values
+}
+
+/*
+ * Sentinel renders the last entry of an infinitely scrolling list, which
+ * replaces itself with the next items once scrolled into view:
+ *
+ *	<tr hx-get="/_mx/lazy/rows?page=2" hx-trigger="revealed"
+ *		hx-swap="outerHTML" aria-busy="true"><td colspan="3">Loading…</td></tr>
+ */
+func (reg *Registry) Sentinel(props SentinelProps) string {
+	global := props.GlobalProps
+	global.Class = append([]string{"lazy-sentinel"}, global.Class...)
+	global.Htmx = hx(global.Htmx, fragmentURL(reg.Register(props.Key, props.Fragment), props.Params), "revealed")
+	global.Aria = utils.With(global.Aria, "busy", "true")
+
+	switch strings.ToLower(props.Tag) {
+	case "tr":
+		td := elements.TdProps{InnerHTML: props.Placeholder}
+		if props.Colspan > 1 {
+			td.Colspan = &props.Colspan
+		}
+		return elements.Tr(elements.TrProps{GlobalProps: global, InnerHTML: elements.Td(td)})
+	case "li":
+		return elements.Li(elements.LiProps{GlobalProps: global, InnerHTML: props.Placeholder})
+	}
+	return elements.Div(elements.DivProps{GlobalProps: global, InnerHTML: props.Placeholder})
+}
+
+// Sentinel renders a sentinel of Default
+func Sentinel(props SentinelProps) string {
+	return Default.Sentinel(props)
+}
+
+/*
+ * Skeleton renders n grey placeholder lines, hidden from assistive
+ * technology, to be styled through the "skeleton" class
+ */
+func Skeleton(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteString(elements.Div(elements.DivProps{
+			GlobalProps: elements.GlobalProps{Class: []string{"skeleton-line"}},
+		}))
+	}
+	return elements.Div(elements.DivProps{
+		GlobalProps: elements.GlobalProps{
+			Class: []string{"skeleton"},
+			Aria:  utils.AriaRoles{"hidden": "true"},
+		},
+		InnerHTML: b.String(),
+	})
+}
+
+// hx adds the fragment request to the caller's own htmx attributes
+func hx(props utils.HtmxProps, u, trigger string) utils.HtmxProps {
+	out := utils.HtmxProps{
+		"get":     strings.ReplaceAll(u, "&", "&amp;"),
+		"trigger": trigger,
+		"swap":    "outerHTML",
+	}
+	for k, v := range props {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+/*
+ * Package lazy defers slow parts of a page to htmx requests made once the
+ * page is shown: sections loaded right after the page (hx-trigger="load")
+ * and infinite scroll sentinels loaded when scrolled into view
+ * (hx-trigger="revealed"). The fragments behind them are registered
+ * automatically the first time they render, under a URL derived from the
+ * key the caller gives, and served by Handler.
+ *
+ *	http.Handle(lazy.DefaultPrefix, lazy.Handler())
+ *
+ *	func dashboard(w http.ResponseWriter, r *http.Request) {
+ *		fmt.Fprint(w, lazy.Lazy("revenue", func(ctx context.Context) string {
+ *			return revenuePanel(ctx)
+ *		}, lazy.Skeleton(4)))
+ *	}
+ *
+ * The key, not the function, names the URL, so it stays the same across
+ * restarts, deploys and instances. When several instances share traffic,
+ * or pages are cached, also register fragments with Handle at startup so
+ * an instance that has not rendered the page yet can serve them.
+ */
+package lazy
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Where Handler is expected to be mounted
+const DefaultPrefix = "/_mx/lazy/"
+
+/*
+ * Fragment renders the deferred HTML. It runs in the fragment's own
+ * request, not the page's, and the first function registered under a key
+ * serves every later render, so it must not rely on variables captured
+ * from the page request. Everything it needs comes from its own request:
+ * Params added by the page, read with Query, and the user's session.
+ * Params are in the URL, so check the user may see what they select as
+ * for any other request.
+ */
+type Fragment func(ctx context.Context) string
+
+/*
+ * A set of fragments served under a common URL prefix
+ */
+type Registry struct {
+	prefix string
+
+	mu        sync.RWMutex
+	fragments map[string]Fragment
+}
+
+// NewRegistry serves fragments under prefix, such as DefaultPrefix
+func NewRegistry(prefix string) *Registry {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &Registry{
+		prefix:    prefix,
+		fragments: map[string]Fragment{},
+	}
+}
+
+// The registry the package functions use, served at DefaultPrefix
+var Default = NewRegistry(DefaultPrefix)
+
+// Handler serves the fragments of Default
+func Handler() http.Handler {
+	return Default
+}
+
+/*
+ * Register serves fn under key, unless a fragment is registered there
+ * already, and returns the URL. The builders call it for their fragment
+ * on every render. Keys name code, not data: pass per-request values as
+ * Params. It panics on an empty key, or a nil fn with nothing registered.
+ */
+func (reg *Registry) Register(key string, fn Fragment) string {
+	if key == "" {
+		panic("lazy: a fragment needs a key")
+	}
+
+	reg.mu.RLock()
+	_, ok := reg.fragments[key]
+	reg.mu.RUnlock()
+	if !ok {
+		if fn == nil {
+			panic("lazy: no fragment registered as " + key)
+		}
+		reg.mu.Lock()
+		if _, ok := reg.fragments[key]; !ok {
+			reg.fragments[key] = fn
+		}
+		reg.mu.Unlock()
+	}
+	return reg.prefix + url.PathEscape(key)
+}
+
+/*
+ * Handle serves fn under key at startup, before any page renders, and
+ * returns its URL. Builders given the same key then reuse it. Like
+ * http.ServeMux, it panics on an empty or already registered key.
+ */
+func (reg *Registry) Handle(key string, fn Fragment) string {
+	if key == "" || fn == nil {
+		panic("lazy: Handle needs a key and a fragment")
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if _, ok := reg.fragments[key]; ok {
+		panic("lazy: fragment " + key + " registered twice")
+	}
+	reg.fragments[key] = fn
+	return reg.prefix + url.PathEscape(key)
+}
+
+// Handle registers a fragment of Default at startup
+func Handle(key string, fn Fragment) string {
+	return Default.Handle(key, fn)
+}
+
+func (reg *Registry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, reg.prefix)
+	reg.mu.RLock()
+	fn, ok := reg.fragments[name]
+	reg.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), requestKey{}, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(fn(ctx)))
+}
+
+type requestKey struct{}
+
+// Request returns the fragment request a Fragment runs in
+func Request(ctx context.Context) *http.Request {
+	r, _ := ctx.Value(requestKey{}).(*http.Request)
+	return r
+}
+
+// Query returns the Params the fragment was rendered with
+func Query(ctx context.Context) url.Values {
+	if r := Request(ctx); r != nil {
+		return r.URL.Query()
+	}
+	return url.Values{}
+}
+
+// fragmentURL adds params to a fragment URL
+func fragmentURL(u string, params url.Values) string {
+	if len(params) == 0 {
+		return u
+	}
+	return u + "?" + params.Encode()
+}
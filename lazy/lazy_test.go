@@ -0,0 +1,90 @@
+package lazy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func panel(ctx context.Context) string {
+	return "<p>" + Query(ctx).Get("id") + "</p>"
+}
+
+func TestSection(t *testing.T) {
+	reg := NewRegistry("/lazy/")
+
+	page := reg.Section(SectionProps{Key: "panel", Fragment: panel, Placeholder: Skeleton(2), Params: url.Values{"id": {"7"}}})
+	if !strings.Contains(page, `hx-get="/lazy/panel?id=7"`) || !strings.Contains(page, `hx-trigger="load"`) {
+		t.Fatalf("unexpected section %s", page)
+	}
+
+	w := httptest.NewRecorder()
+	reg.ServeHTTP(w, httptest.NewRequest("GET", "/lazy/panel?id=7", nil))
+	if w.Body.String() != "<p>7</p>" {
+		t.Fatalf("unexpected fragment %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	reg.ServeHTTP(w, httptest.NewRequest("GET", "/lazy/other", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unregistered fragment served: %d", w.Code)
+	}
+}
+
+func TestLazy(t *testing.T) {
+	old := Default
+	Default = NewRegistry(DefaultPrefix)
+	defer func() { Default = old }()
+
+	// Every render passes a new closure; the key keeps one URL and the
+	// first registration serves it
+	for i := 0; i < 3; i++ {
+		n := i
+		page := Lazy("slow panel", func(ctx context.Context) string {
+			return "<p>" + strconv.Itoa(n) + "</p>"
+		}, "…")
+		if !strings.Contains(page, `hx-get="/_mx/lazy/slow%20panel"`) || !strings.Contains(page, ">…</div>") {
+			t.Fatalf("unexpected section %s", page)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/_mx/lazy/slow%20panel", nil))
+	if w.Body.String() != "<p>0</p>" {
+		t.Fatalf("unexpected fragment %q", w.Body.String())
+	}
+}
+
+func TestRegistration(t *testing.T) {
+	reg := NewRegistry("/lazy")
+	if u := reg.Handle("rows", panel); u != "/lazy/rows" {
+		t.Fatalf("unexpected URL %q", u)
+	}
+
+	for name, fn := range map[string]func(){
+		"twice":      func() { reg.Handle("rows", panel) },
+		"empty":      func() { reg.Handle("", panel) },
+		"nil handle": func() { reg.Handle("nil", nil) },
+		"no key":     func() { reg.Section(SectionProps{Fragment: panel}) },
+		"unknown":    func() { reg.Sentinel(SentinelProps{Key: "missing"}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+
+	// A fragment registered at startup serves builders given its key
+	s := reg.Sentinel(SentinelProps{Key: "rows", Tag: "tr", Colspan: 3, Params: url.Values{"page": {"2"}}})
+	if !strings.Contains(s, `hx-get="/lazy/rows?page=2"`) || !strings.Contains(s, `hx-trigger="revealed"`) || !strings.Contains(s, `colspan="3"`) {
+		t.Fatalf("unexpected sentinel %s", s)
+	}
+}
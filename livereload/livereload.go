@@ -0,0 +1,162 @@
+/*
+ * Package livereload refreshes browsers during development. Pages receive
+ * a small script that listens on a Server-Sent Events endpoint and reloads
+ * when told to, restoring the scroll position afterwards.
+ */
+package livereload
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/bitpartio/Mx/elements"
+)
+
+// Endpoint the injected script listens on
+const Path = "/_mx/livereload"
+
+// How often idle connections are kept alive
+const heartbeat = 15 * time.Second
+
+const script = `(function () {
+  var key = "mx:scroll:" + location.pathname;
+  var saved = sessionStorage.getItem(key);
+  if (saved) {
+    sessionStorage.removeItem(key);
+    var pos = JSON.parse(saved);
+    var restore = function () { window.scrollTo(pos[0], pos[1]); };
+    if (document.readyState === "complete") restore();
+    else window.addEventListener("load", restore);
+  }
+  var source = new EventSource("` + Path + `");
+  source.addEventListener("reload", function () {
+    sessionStorage.setItem(key, JSON.stringify([window.scrollX, window.scrollY]));
+    location.reload();
+  });
+  source.addEventListener("build-error", function (e) {
+    console.error("[mx] build failed\n" + e.data);
+  });
+})();`
+
+// Tag returns the live-reload <script> element
+func Tag() string {
+	return Script(ScriptProps{InnerHTML: script})
+}
+
+/*
+ * Inject adds the live-reload script to an HTML document, just before
+ * </body> when present and at the end otherwise.
+ */
+func Inject(page []byte) []byte {
+	tag := []byte(Tag())
+
+	i := lastIndexFold(page, "</body>")
+	if i < 0 {
+		return append(page, tag...)
+	}
+
+	out := make([]byte, 0, len(page)+len(tag))
+	out = append(out, page[:i]...)
+	out = append(out, tag...)
+	out = append(out, page[i:]...)
+	return out
+}
+
+// lastIndexFold is bytes.LastIndex ignoring ASCII case, without changing
+// offsets the way lowering multi-byte text can
+func lastIndexFold(s []byte, substr string) int {
+	for i := len(s) - len(substr); i >= 0; i-- {
+		if bytes.EqualFold(s[i:i+len(substr)], []byte(substr)) {
+			return i
+		}
+	}
+	return -1
+}
+
+type event struct {
+	name string
+	data string
+}
+
+/*
+ * Server is the Server-Sent Events endpoint connected browsers listen on.
+ * Mount it at Path.
+ */
+type Server struct {
+	mu      sync.Mutex
+	clients map[chan event]struct{}
+}
+
+// New
+func New() *Server {
+	return &Server{clients: map[chan event]struct{}{}}
+}
+
+// Reload tells every connected browser to reload
+func (s *Server) Reload() {
+	s.broadcast(event{name: "reload", data: "reload"})
+}
+
+// Error reports a failed build to every connected browser's console
+func (s *Server) Error(msg string) {
+	s.broadcast(event{name: "build-error", data: msg})
+}
+
+func (s *Server) broadcast(e event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		select {
+		case c <- e:
+		default:
+		}
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	c := make(chan event, 1)
+	s.mu.Lock()
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case e := <-c:
+			fmt.Fprintf(w, "event: %s\n", e.name)
+			for _, line := range strings.Split(e.data, "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+			fmt.Fprint(w, "\n")
+		}
+		flusher.Flush()
+	}
+}
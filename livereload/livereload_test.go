@@ -0,0 +1,76 @@
+package livereload
+
+import (
+	"bufio"
+	"context"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInject(t *testing.T) {
+	tag := Tag()
+
+	got := string(Inject([]byte("<html><body><p>hi</p></BODY></html>")))
+	if want := "<html><body><p>hi</p>" + tag + "</BODY></html>"; got != want {
+		t.Fatalf("got %s", got)
+	}
+
+	got = string(Inject([]byte("<p>fragment</p>")))
+	if got != "<p>fragment</p>"+tag {
+		t.Fatalf("got %s", got)
+	}
+
+	// The last </body> is the document's, not one quoted in a script
+	got = string(Inject([]byte(`<body><script>"</body>"</script></body>`)))
+	if !strings.HasSuffix(got, tag+"</body>") {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestServer(t *testing.T) {
+	s := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	req := httptest.NewRequest("GET", srv.URL, nil).WithContext(ctx)
+	req.RequestURI = ""
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type %q", ct)
+	}
+
+	// The client is registered once the headers are flushed
+	for {
+		s.mu.Lock()
+		n := len(s.clients)
+		s.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		runtime.Gosched()
+	}
+	s.Error("line 1\nline 2")
+
+	r := bufio.NewReader(res.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	if strings.Join(lines, "|") != "event: build-error|data: line 1|data: line 2" {
+		t.Fatalf("got %q", lines)
+	}
+}
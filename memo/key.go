@@ -0,0 +1,170 @@
+package memo
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Deepest nesting followed when hashing props, guards against cycles
+const maxDepth = 32
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ErrTooDeep reports props nested deeper than Key follows, such as a cycle
+var ErrTooDeep = errors.New("memo: props nested too deeply to key")
+
+/*
+ * Key derives the cache key for a component name and its props. Props are
+ * hashed structurally: pointers are followed and map keys are sorted, so
+ * equal props produce the same key and unequal ones different keys.
+ *
+ * Named functions, such as option functions (AOptions.Rel.NoFollow), are
+ * keyed by identity and never called. Closures and method values share
+ * their code with every other closure from the same literal, whatever they
+ * captured, so props holding them cannot be keyed and return an error, as
+ * do channels, unsafe pointers and props nested deeper than 32 levels.
+ */
+func Key(name string, props interface{}) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	if err := hashValue(h, reflect.ValueOf(props), 0); err != nil {
+		return "", err
+	}
+	return name + ":" + hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
+func hashValue(h hash.Hash, v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return ErrTooDeep
+	}
+	if !v.IsValid() {
+		h.Write([]byte{'n'})
+		return nil
+	}
+
+	if v.Type() == timeType && v.CanInterface() {
+		t := v.Interface().(time.Time)
+		writeInt(h, t.UnixNano())
+		h.Write([]byte(t.Location().String()))
+		return nil
+	}
+
+	h.Write([]byte(v.Kind().String()))
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{0})
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeInt(h, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeInt(h, int64(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		writeInt(h, int64(math.Float64bits(v.Float())))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeInt(h, int64(math.Float64bits(real(c))))
+		writeInt(h, int64(math.Float64bits(imag(c))))
+	case reflect.String:
+		writeInt(h, int64(v.Len()))
+		h.Write([]byte(v.String()))
+	case reflect.Slice, reflect.Array:
+		writeInt(h, int64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := hashValue(h, v.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		writeInt(h, int64(v.Len()))
+		entries := make([][2][]byte, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := sha256.New()
+			if err := hashValue(k, iter.Key(), depth+1); err != nil {
+				return err
+			}
+			e := sha256.New()
+			if err := hashValue(e, iter.Value(), depth+1); err != nil {
+				return err
+			}
+			entries = append(entries, [2][]byte{k.Sum(nil), e.Sum(nil)})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return string(entries[i][0]) < string(entries[j][0])
+		})
+		for _, entry := range entries {
+			h.Write(entry[0])
+			h.Write(entry[1])
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			h.Write([]byte(t.Field(i).Name))
+			if err := hashValue(h, v.Field(i), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			h.Write([]byte{'n'})
+			return nil
+		}
+		return hashValue(h, v.Elem(), depth+1)
+	case reflect.Func:
+		if v.IsNil() {
+			h.Write([]byte{'n'})
+			return nil
+		}
+		name, ok := funcName(v)
+		if !ok {
+			return fmt.Errorf("memo: cannot key props holding a closure or method value (%s)", v.Type())
+		}
+		writeInt(h, int64(len(name)))
+		h.Write([]byte(name))
+	case reflect.Chan, reflect.UnsafePointer:
+		return fmt.Errorf("memo: cannot key props holding a %s", v.Type())
+	}
+	return nil
+}
+
+// closureName matches the symbols the compiler gives function literals,
+// such as "pkg.Render.func1" or "pkg.glob..func2"
+var closureName = regexp.MustCompile(`\.func\d+`)
+
+/*
+ * funcName names the named function v holds, false for function literals
+ * and method values, which may capture state the name does not identify
+ */
+func funcName(v reflect.Value) (string, bool) {
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return "", false
+	}
+	name := fn.Name()
+	if strings.HasSuffix(name, "-fm") || closureName.MatchString(name[strings.LastIndex(name, "/")+1:]) {
+		return "", false
+	}
+	return name, true
+}
+
+func writeInt(h hash.Hash, i int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(i))
+	h.Write(b[:])
+}
@@ -0,0 +1,262 @@
+/*
+ * Package memo caches the rendered output of pure components. Entries are
+ * keyed by a component name plus a hash of its props, expire after a TTL,
+ * are evicted least-recently-used once the cache is full and can be
+ * invalidated in groups by tag.
+ */
+package memo
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Default number of entries held when Options.MaxEntries is zero
+const DefaultMaxEntries = 1024
+
+/*
+ * Options
+ */
+type Options struct {
+	// Entries held before the least recently used is evicted
+	MaxEntries int
+	// Lifetime of an entry, zero never expires
+	TTL time.Duration
+}
+
+type entry struct {
+	key     string
+	value   string
+	tags    []string
+	expires time.Time
+}
+
+// An in-flight render other callers can wait on
+type call struct {
+	wg    sync.WaitGroup
+	value string
+	err   error
+	// Recovered from render, panicked again in every caller
+	panic interface{}
+}
+
+/*
+ * Cache is safe for concurrent use. Concurrent misses on the same key
+ * render only once; the other callers wait for and share that result,
+ * error or panic included.
+ */
+type Cache struct {
+	mu       sync.Mutex
+	opts     Options
+	ll       *list.List
+	items    map[string]*list.Element
+	tags     map[string]map[string]struct{}
+	inflight map[string]*call
+	// Bumped by every removal, so renders begun before an Invalidate do
+	// not store their stale output after it
+	gen uint64
+
+	now func() time.Time
+}
+
+// New
+func New(opts Options) *Cache {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultMaxEntries
+	}
+
+	return &Cache{
+		opts:     opts,
+		ll:       list.New(),
+		items:    map[string]*list.Element{},
+		tags:     map[string]map[string]struct{}{},
+		inflight: map[string]*call{},
+		now:      time.Now,
+	}
+}
+
+// Default cache used by the package-level Memo
+var Default = New(Options{})
+
+/*
+ * Memo returns the cached output for the component name and props,
+ * calling render on a miss. Tags label the entry for Invalidate. Props
+ * Key cannot hash are rendered every time, uncached.
+ */
+func (c *Cache) Memo(name string, props interface{}, render func() string, tags ...string) string {
+	s, _ := c.MemoErr(name, props, func() (string, error) { return render(), nil }, tags...)
+	return s
+}
+
+/*
+ * MemoErr is Memo for renders that can fail. Failed and panicking renders
+ * are never stored: callers waiting on them get the same error or panic.
+ */
+func (c *Cache) MemoErr(name string, props interface{}, render func() (string, error), tags ...string) (string, error) {
+	key, err := Key(name, props)
+	if err != nil {
+		return render()
+	}
+
+	c.mu.Lock()
+	if value, ok := c.get(key); ok {
+		c.mu.Unlock()
+		return value, nil
+	}
+	if inflight, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		inflight.wg.Wait()
+		if inflight.panic != nil {
+			panic(inflight.panic)
+		}
+		return inflight.value, inflight.err
+	}
+	cl := &call{}
+	cl.wg.Add(1)
+	c.inflight[key] = cl
+	gen := c.gen
+	c.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			cl.panic = r
+		}
+
+		c.mu.Lock()
+		delete(c.inflight, key)
+		if cl.panic == nil && cl.err == nil && c.gen == gen {
+			c.set(key, cl.value, tags)
+		}
+		c.mu.Unlock()
+		cl.wg.Done()
+
+		if cl.panic != nil {
+			panic(cl.panic)
+		}
+	}()
+
+	cl.value, cl.err = render()
+	return cl.value, cl.err
+}
+
+// Memo using the Default cache
+func Memo(name string, props interface{}, render func() string, tags ...string) string {
+	return Default.Memo(name, props, render, tags...)
+}
+
+/*
+ * Func wraps a component builder, such as elements.Nav, so every call is
+ * served from the cache:
+ *
+ *   CachedNav := memo.Func(cache, "nav", Nav, "navigation")
+ *   s := CachedNav(NavProps{InnerHTML: links})
+ */
+func Func[P any](c *Cache, name string, component func(props P) string, tags ...string) func(props P) string {
+	return func(props P) string {
+		return c.Memo(name, props, func() string { return component(props) }, tags...)
+	}
+}
+
+// Invalidate removes every entry labelled with any of the tags
+func (c *Cache) Invalidate(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if el, ok := c.items[key]; ok {
+				c.remove(el)
+			}
+		}
+	}
+}
+
+// Delete removes the entry for a component name and props
+func (c *Cache) Delete(name string, props interface{}) {
+	key, err := Key(name, props)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// Purge empties the cache
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	c.ll.Init()
+	c.items = map[string]*list.Element{}
+	c.tags = map[string]map[string]struct{}{}
+}
+
+// Len reports the number of cached entries, including expired ones not yet
+// reclaimed
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+func (c *Cache) get(key string) (string, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+
+	e := el.Value.(*entry)
+	if !e.expires.IsZero() && !c.now().Before(e.expires) {
+		c.remove(el)
+		return "", false
+	}
+
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *Cache) set(key, value string, tags []string) {
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+
+	e := &entry{key: key, value: value, tags: tags}
+	if c.opts.TTL > 0 {
+		e.expires = c.now().Add(c.opts.TTL)
+	}
+
+	c.items[key] = c.ll.PushFront(e)
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = map[string]struct{}{}
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+
+	for c.ll.Len() > c.opts.MaxEntries {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	e := el.Value.(*entry)
+
+	c.ll.Remove(el)
+	delete(c.items, e.key)
+	for _, tag := range e.tags {
+		delete(c.tags[tag], e.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package memo
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/bitpartio/Mx/elements"
+	. "github.com/bitpartio/Mx/utils"
+)
+
+func TestMemo(t *testing.T) {
+	c := New(Options{})
+
+	var renders int32
+	card := func(props PProps) string {
+		atomic.AddInt32(&renders, 1)
+		return P(props)
+	}
+	cached := Func(c, "card", card, "cards")
+
+	a := cached(PProps{InnerHTML: "one", GlobalProps: GlobalProps{Class: []string{"card"}}})
+	b := cached(PProps{InnerHTML: "one", GlobalProps: GlobalProps{Class: []string{"card"}}})
+	cached(PProps{InnerHTML: "two"})
+
+	if a != b {
+		t.Fatal("equal props rendered different output")
+	}
+	if renders != 2 {
+		t.Fatalf("expected 2 renders, got %d", renders)
+	}
+
+	c.Invalidate("cards")
+	if c.Len() != 0 {
+		t.Fatalf("expected empty cache after invalidation, got %d", c.Len())
+	}
+}
+
+func key(t *testing.T, name string, props interface{}) string {
+	t.Helper()
+	k, err := Key(name, props)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestKeyFollowsPointers(t *testing.T) {
+	a := key(t, "col", ColProps{Span: Ptr(2)})
+	b := key(t, "col", ColProps{Span: Ptr(2)})
+	c := key(t, "col", ColProps{Span: Ptr(3)})
+
+	if a != b {
+		t.Fatal("pointers to equal values produced different keys")
+	}
+	if a == c {
+		t.Fatal("different values produced the same key")
+	}
+}
+
+func TestKeyFuncs(t *testing.T) {
+	rel := AProps{Rel: []func() ARelOption{AOptions.Rel.NoFollow}}
+	if key(t, "a", rel) != key(t, "a", rel) {
+		t.Fatal("equal option functions produced different keys")
+	}
+	if key(t, "a", rel) == key(t, "a", AProps{Rel: []func() ARelOption{AOptions.Rel.NoOpener}}) {
+		t.Fatal("different option functions produced the same key")
+	}
+
+	// Keying must not run the function: it may be a render closure
+	calls := 0
+	closure := func(s string) func() string { return func() string { calls++; return s } }
+	for _, fn := range []interface{}{closure("a"), time.Now().String} {
+		if _, err := Key("f", fn); err == nil {
+			t.Errorf("keyed %T", fn)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("called the closure %d times", calls)
+	}
+	if _, err := Key("f", strconv.Itoa); err != nil {
+		t.Errorf("named function: %v", err)
+	}
+
+	type node struct{ Next *node }
+	n := &node{}
+	n.Next = n
+	if _, err := Key("cycle", n); err != ErrTooDeep {
+		t.Fatalf("got %v for a cycle", err)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	c := New(Options{MaxEntries: 2})
+	render := func(i int) string {
+		return c.Memo("n", i, func() string { return strconv.Itoa(i) })
+	}
+
+	render(1)
+	render(2)
+	render(1) // 1 becomes most recently used
+	render(3) // evicts 2
+
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", c.Len())
+	}
+	if _, ok := c.items[key(t, "n", 2)]; ok {
+		t.Fatal("least recently used entry was not evicted")
+	}
+	if _, ok := c.items[key(t, "n", 1)]; !ok {
+		t.Fatal("recently used entry was evicted")
+	}
+}
+
+func TestTTL(t *testing.T) {
+	now := time.Now()
+	c := New(Options{TTL: time.Minute})
+	c.now = func() time.Time { return now }
+
+	var renders int
+	render := func() string {
+		renders++
+		return "footer"
+	}
+
+	c.Memo("footer", nil, render)
+	c.Memo("footer", nil, render)
+	now = now.Add(2 * time.Minute)
+	c.Memo("footer", nil, render)
+
+	if renders != 2 {
+		t.Fatalf("expected 2 renders, got %d", renders)
+	}
+}
+
+func TestConcurrentMissRendersOnce(t *testing.T) {
+	c := New(Options{})
+
+	var renders int32
+	started, release := make(chan struct{}), make(chan struct{})
+	render := func() string {
+		atomic.AddInt32(&renders, 1)
+		close(started)
+		<-release
+		return "slow"
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	run := func(i int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.Memo("slow", nil, render)
+		}()
+	}
+
+	// Once the first render is under way, every other caller either waits
+	// on it or, if it already finished, reads the cache
+	run(0)
+	<-started
+	for i := 1; i < len(results); i++ {
+		run(i)
+	}
+	close(release)
+	wg.Wait()
+
+	if renders != 1 {
+		t.Fatalf("expected 1 render, got %d", renders)
+	}
+	for _, r := range results {
+		if r != "slow" {
+			t.Fatalf("unexpected result %q", r)
+		}
+	}
+}
+
+func TestMemoErrNotStored(t *testing.T) {
+	c := New(Options{})
+	fail := errors.New("fail")
+
+	if _, err := c.MemoErr("n", nil, func() (string, error) { return "partial", fail }); err != fail {
+		t.Fatalf("got %v", err)
+	}
+	if c.Len() != 0 {
+		t.Fatal("failed render was stored")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic was swallowed")
+			}
+		}()
+		c.Memo("n", nil, func() string { panic("boom") })
+	}()
+	if c.Len() != 0 {
+		t.Fatal("panicked render was stored")
+	}
+}
+
+func TestInvalidateDuringRender(t *testing.T) {
+	c := New(Options{})
+
+	c.Memo("n", nil, func() string {
+		c.Invalidate("t")
+		return "stale"
+	}, "t")
+	if c.Len() != 0 {
+		t.Fatal("render begun before Invalidate was stored after it")
+	}
+}
@@ -0,0 +1,263 @@
+/*
+ * Package microdata annotates markup with schema.org microdata taken from
+ * Go structs. Fields carry their property name in an itemprop tag:
+ *
+ *	type Product struct {
+ *		Name   string  `itemprop:"name"`
+ *		Image  string  `itemprop:"image,url"`
+ *		Offers []Offer `itemprop:"offers"`
+ *	}
+ *
+ *	func (Product) Itemtype() string { return "https://schema.org/Product" }
+ *
+ * Properties shown on the page are annotated where they are shown, with
+ * Prop on the visible element; Scope adds the rest as hidden <meta> and
+ * <link> elements:
+ *
+ *	microdata.Scope(p, H2(HProps{
+ *		GlobalProps: microdata.Prop("name"),
+ *		InnerHTML:   html.EscapeString(p.Name),
+ *	}))
+ *
+ * The ",url" option renders a hidden value as a <link href> rather than a
+ * <meta content>, as microdata requires for URL properties. Struct fields
+ * become nested items, typed by their own Itemtype method or an itemtype
+ * tag on the field. Nil pointers and interfaces, empty strings and slices
+ * and zero times are left out; other values, such as a price of 0 or
+ * false, are meaningful and kept.
+ */
+package microdata
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/bitpartio/Mx/elements"
+	xhtml "golang.org/x/net/html"
+)
+
+// Implemented by structs that know their vocabulary type
+type Typed interface {
+	Itemtype() string
+}
+
+// Implemented by structs with a global identifier, such as a URL or ISBN
+type Identified interface {
+	Itemid() string
+}
+
+/*
+ * Item returns the attributes that make an element the item v describes,
+ * for an element whose children hold its properties.
+ */
+func Item(v interface{}) GlobalProps {
+	props := GlobalProps{Itemscope: true}
+	if t, ok := v.(Typed); ok && t.Itemtype() != "" {
+		props.Itemtype = []string{t.Itemtype()}
+	}
+	if i, ok := v.(Identified); ok {
+		props.Itemid = i.Itemid()
+	}
+	return props
+}
+
+// Prop returns the attributes that make an element's content the property
+// name of the enclosing item
+func Prop(name string) GlobalProps {
+	return GlobalProps{Itemprop: []string{name}}
+}
+
+// ItemProp returns the attributes that make an element both the item v
+// describes and the property name of the enclosing item
+func ItemProp(name string, v interface{}) GlobalProps {
+	props := Item(v)
+	props.Itemprop = []string{name}
+	return props
+}
+
+/*
+ * Properties renders v's tagged fields as invisible <meta> and <link>
+ * elements, and nested items as <div>s, to be placed inside the element
+ * carrying Item(v).
+ */
+func Properties(v interface{}) string {
+	return properties(v, nil)
+}
+
+/*
+ * Scope wraps innerHTML, the visible markup, in a <div> that is the item v
+ * describes. Properties innerHTML already annotates with Prop or ItemProp
+ * are left to it; the others follow as hidden elements.
+ */
+func Scope(v interface{}, innerHTML string) string {
+	return Div(DivProps{
+		GlobalProps: Item(v),
+		InnerHTML:   innerHTML + properties(v, annotated(innerHTML)),
+	})
+}
+
+func properties(v interface{}, skip map[string]bool) string {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+
+	var b strings.Builder
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, ok := f.Tag.Lookup("itemprop")
+		if !ok || tag == "-" || !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if skip[name] {
+			continue
+		}
+		p := property{
+			name:     name,
+			url:      opts == "url",
+			itemtype: f.Tag.Get("itemtype"),
+		}
+		p.write(&b, rv.Field(i))
+	}
+	return b.String()
+}
+
+/*
+ * annotated returns the itemprop names in markup that belong to the item
+ * enclosing it, skipping those inside nested items.
+ */
+func annotated(markup string) map[string]bool {
+	names := map[string]bool{}
+
+	// Open elements, true for those starting a nested item
+	var open []bool
+	nested := 0
+
+	z := xhtml.NewTokenizer(strings.NewReader(markup))
+	for {
+		switch z.Next() {
+		case xhtml.ErrorToken:
+			return names
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			t := z.Token()
+			scope := false
+			for _, a := range t.Attr {
+				switch a.Key {
+				case "itemprop":
+					if nested == 0 {
+						for _, name := range strings.Fields(a.Val) {
+							names[name] = true
+						}
+					}
+				case "itemscope":
+					scope = true
+				}
+			}
+			if t.Type == xhtml.SelfClosingTagToken || void[t.Data] {
+				continue
+			}
+			open = append(open, scope)
+			if scope {
+				nested++
+			}
+
+		case xhtml.EndTagToken:
+			if len(open) == 0 {
+				continue
+			}
+			if open[len(open)-1] {
+				nested--
+			}
+			open = open[:len(open)-1]
+		}
+	}
+}
+
+// Elements without an end tag
+var void = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+type property struct {
+	name     string
+	url      bool
+	itemtype string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (p property) write(b *strings.Builder, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			p.write(b, v.Index(i))
+		}
+
+	case v.Type() == timeType:
+		t := v.Interface().(time.Time)
+		if !t.IsZero() {
+			p.value(b, t.Format(time.RFC3339))
+		}
+
+	case v.Kind() == reflect.Struct:
+		item := Item(v.Interface())
+		if p.itemtype != "" {
+			item.Itemtype = []string{p.itemtype}
+		}
+		item.Itemprop = []string{p.name}
+		b.WriteString(Div(DivProps{
+			GlobalProps: item,
+			InnerHTML:   properties(v.Interface(), nil),
+		}))
+
+	case v.Kind() == reflect.String && v.Len() == 0:
+
+	default:
+		p.value(b, format(v))
+	}
+}
+
+func (p property) value(b *strings.Builder, s string) {
+	itemprop := []string{p.name}
+	if p.url {
+		b.WriteString(Link(LinkProps{
+			GlobalProps: GlobalProps{Itemprop: itemprop},
+			Href:        html.EscapeString(s),
+		}))
+		return
+	}
+	b.WriteString(Meta(MetaProps{
+		GlobalProps: GlobalProps{Itemprop: itemprop},
+		Content:     html.EscapeString(s),
+	}))
+}
+
+func format(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.String:
+		return v.String()
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,135 @@
+package microdata
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/bitpartio/Mx/elements"
+)
+
+type offer struct {
+	Price    float64 `itemprop:"price"`
+	Currency string  `itemprop:"priceCurrency"`
+}
+
+func (offer) Itemtype() string { return "https://schema.org/Offer" }
+
+type product struct {
+	Name     string    `itemprop:"name"`
+	Image    string    `itemprop:"image,url"`
+	Released time.Time `itemprop:"releaseDate"`
+	Offers   []offer   `itemprop:"offers"`
+	Brand    *struct {
+		Name string `itemprop:"name"`
+	} `itemprop:"brand" itemtype:"https://schema.org/Brand"`
+	Note string
+	SKU  string `itemprop:"-"`
+}
+
+func (product) Itemtype() string { return "https://schema.org/Product" }
+
+func (product) Itemid() string { return "urn:sku:1" }
+
+// Collapses the builders' padding to single spaces
+func squash(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestGlobalProps(t *testing.T) {
+	got := squash(Div(DivProps{GlobalProps: GlobalProps{
+		ID:        "p",
+		Itemscope: true,
+		Itemtype:  []string{"https://schema.org/Product", "https://schema.org/Thing"},
+		Itemprop:  []string{"item", "mainEntity"},
+		Itemref:   []string{"a", "b"},
+	}}))
+	for _, want := range []string{
+		` itemscope `,
+		`itemtype="https://schema.org/Product https://schema.org/Thing"`,
+		`itemprop="item mainEntity"`,
+		`itemref="a b"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	if strings.Contains(Div(DivProps{}), "item") {
+		t.Error("unset item attributes rendered")
+	}
+}
+
+func TestProperties(t *testing.T) {
+	got := squash(Properties(product{
+		Name:     `Mug & "Cup"`,
+		Image:    "/mug.png",
+		Released: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Offers:   []offer{{Price: 9.5, Currency: "EUR"}},
+		Note:     "untagged",
+		SKU:      "1",
+	}))
+	for _, want := range []string{
+		`content="Mug &amp; &#34;Cup&#34;" itemprop="name"`,
+		`<link itemprop="image" href="/mug.png" />`,
+		`content="2024-01-02T00:00:00Z" itemprop="releaseDate"`,
+		`itemscope itemtype="https://schema.org/Offer" itemprop="offers"`,
+		`content="9.5" itemprop="price"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"untagged", "brand", `"1"`} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("rendered %s in %s", unwanted, got)
+		}
+	}
+}
+
+func TestPropertiesZero(t *testing.T) {
+	type rating struct {
+		Value    int         `itemprop:"ratingValue"`
+		Best     float64     `itemprop:"bestRating"`
+		Verified bool        `itemprop:"verified"`
+		Author   *string     `itemprop:"author"`
+		Note     interface{} `itemprop:"note"`
+		Tags     []string    `itemprop:"keywords"`
+	}
+	got := squash(Properties(offer{Price: 0, Currency: "EUR"}) + Properties(rating{}))
+	for _, want := range []string{
+		`content="0" itemprop="price"`,
+		`content="0" itemprop="ratingValue"`,
+		`content="0" itemprop="bestRating"`,
+		`content="false" itemprop="verified"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"author", "note", "keywords"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("rendered %s in %s", unwanted, got)
+		}
+	}
+}
+
+func TestScope(t *testing.T) {
+	p := product{Name: "Mug", Image: "/mug.png", Offers: []offer{{Price: 9.5, Currency: "EUR"}}}
+
+	visible := H2(HProps{GlobalProps: Prop("name"), InnerHTML: "Mug"}) +
+		Img(ImgProps{GlobalProps: Prop("image"), Src: "/mug.png"}) +
+		Div(DivProps{GlobalProps: ItemProp("brand", struct{}{}), InnerHTML: Span(SpanProps{GlobalProps: Prop("offers")})})
+
+	got := squash(Scope(p, visible))
+
+	if !strings.HasPrefix(got, `<div itemscope itemtype="https://schema.org/Product" itemid="urn:sku:1"`) {
+		t.Errorf("item not on the wrapper: %s", got)
+	}
+	if strings.Count(got, `itemprop="name"`) != 1 || strings.Count(got, `itemprop="image"`) != 1 {
+		t.Errorf("visible properties repeated: %s", got)
+	}
+	if !strings.Contains(got, `itemtype="https://schema.org/Offer" itemprop="offers"`) || !strings.Contains(got, `content="9.5" itemprop="price"`) {
+		// offers inside the nested brand belongs to the brand, not the product
+		t.Errorf("unannotated offers left out: %s", got)
+	}
+}
@@ -0,0 +1,325 @@
+/*
+ * Package pagination renders pagination links for listings, numbered,
+ * cursor-based or "load more", that work as plain links and, given an
+ * hx-target, as htmx swaps. Parse and Window do the server side.
+ *
+ *	q := pagination.Parse(r, pagination.QueryOptions{})
+ *	items, total := store.List(q.Offset(), q.PerPage)
+ *	nav := pagination.Pagination(pagination.Props{
+ *		URL:    "/articles",
+ *		Values: r.URL.Query(),
+ *		Query:  q,
+ *		Total:  total,
+ *		Target: "#articles",
+ *	})
+ */
+package pagination
+
+import (
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/utils"
+)
+
+func init() {
+	PaginationOptions = paginationOptions{
+		Style: paginationStyleOptions{
+			Numbered: paginationStyleOptionNumbered,
+			Cursor:   paginationStyleOptionCursor,
+			LoadMore: paginationStyleOptionLoadMore,
+		},
+	}
+}
+
+/*
+ * Props
+ */
+type Props struct {
+	// On the <nav>
+	elements.GlobalProps
+
+	// Numbered when nil
+	Style func() paginationStyleOption
+	// Path of the listing
+	URL string
+	// Query of the current request, kept in the links, e.g. filters
+	Values url.Values
+	// Parameter names, as given to Parse
+	Params QueryOptions
+	// The current page
+	Query Query
+	// Number of items, for Numbered
+	Total int
+	// Pages linked either side of the current one, 2 when zero
+	Window int
+	// Cursors of the next and previous pages, for Cursor and LoadMore.
+	// There is no such page when empty.
+	Next string
+	Prev string
+
+	// Selector of the element to swap; plain links when empty. LoadMore
+	// defaults to "closest nav", replacing itself.
+	Target string
+	// hx-swap; LoadMore defaults to "outerHTML"
+	Swap string
+	// hx-select, picking part of the response
+	Select string
+
+	// "Pagination"
+	Label string
+	// "Previous", "Next" and "Load more"
+	PrevText string
+	NextText string
+	MoreText string
+}
+
+/*
+ * Pagination builds a <nav> of links with rel="prev" and rel="next" and,
+ * for Numbered, the page window with aria-current="page" on the current
+ * page:
+ *
+ *	<nav aria-label="Pagination" class="pagination">
+ *		<a rel="prev" href="/articles?page=2" hx-get="..." hx-target="#articles" hx-push-url="true">Previous</a>
+ *		<a href="/articles">1</a> <span class="gap">…</span> ...
+ *	</nav>
+ *
+ * Numbered and Cursor links push their URL to the history. LoadMore
+ * renders a single link that does not, since reloading that URL would lose
+ * the items before it. Its response should hold the next items and the
+ * next LoadMore link, or none after the last page; with a Target other than
+ * itself, give the <nav> an ID and have the response swap it out of band
+ * with hx-swap-oob.
+ */
+func Pagination(props Props) string {
+	props = props.withDefaults()
+	global := props.GlobalProps
+	global.Class = append([]string{"pagination"}, global.Class...)
+	aria := utils.AriaRoles{"label": html.EscapeString(props.Label)}
+	for k, v := range global.Aria {
+		aria[k] = v
+	}
+	global.Aria = aria
+
+	var inner string
+	switch props.Style().String() {
+	case "cursor":
+		inner = props.cursor()
+	case "load-more":
+		inner = props.loadMore()
+		if inner == "" {
+			return ""
+		}
+	default:
+		inner = props.numbered()
+	}
+
+	return elements.Nav(elements.NavProps{GlobalProps: global, InnerHTML: inner})
+}
+
+func (props Props) withDefaults() Props {
+	props.Params = props.Params.withDefaults()
+	if props.Style == nil {
+		props.Style = PaginationOptions.Style.Numbered
+	}
+	if props.Query.Page < 1 {
+		props.Query.Page = 1
+	}
+	if props.Window <= 0 {
+		props.Window = 2
+	}
+	if props.Label == "" {
+		props.Label = "Pagination"
+	}
+	if props.PrevText == "" {
+		props.PrevText = "Previous"
+	}
+	if props.NextText == "" {
+		props.NextText = "Next"
+	}
+	if props.MoreText == "" {
+		props.MoreText = "Load more"
+	}
+	if props.Style().String() == "load-more" {
+		if props.Target == "" {
+			props.Target = "closest nav"
+		}
+		if props.Swap == "" {
+			props.Swap = "outerHTML"
+		}
+	}
+	return props
+}
+
+func (props Props) numbered() string {
+	pages := Pages(props.Total, props.Query.PerPage)
+	page := props.Query.Page
+
+	// Past the end, previous leads back to the last page
+	prev := page - 1
+	if prev > pages {
+		prev = pages
+	}
+
+	var b strings.Builder
+	b.WriteString(props.link(props.pageURL(prev), page > 1, props.PrevText, relPrev))
+	for _, n := range Window(page, pages, props.Window) {
+		b.WriteString(" ")
+		if n == 0 {
+			b.WriteString(elements.Span(elements.SpanProps{
+				GlobalProps: elements.GlobalProps{Class: []string{"gap"}, Aria: utils.AriaRoles{"hidden": "true"}},
+				InnerHTML:   "…",
+			}))
+			continue
+		}
+
+		a := elements.AProps{InnerHTML: strconv.Itoa(n)}
+		a.Href = html.EscapeString(props.pageURL(n))
+		a.Htmx = props.htmx(props.pageURL(n), true)
+		if n == page {
+			a.Aria = utils.AriaRoles{"current": "page"}
+		}
+		b.WriteString(elements.A(a))
+	}
+	b.WriteString(" ")
+	b.WriteString(props.link(props.pageURL(page+1), page < pages, props.NextText, relNext))
+	return b.String()
+}
+
+func (props Props) cursor() string {
+	return props.link(props.cursorURL(props.Prev), props.Prev != "", props.PrevText, relPrev) + " " +
+		props.link(props.cursorURL(props.Next), props.Next != "", props.NextText, relNext)
+}
+
+// loadMore links the next cursor, or the next page when there is none
+func (props Props) loadMore() string {
+	var next string
+	switch {
+	case props.Next != "":
+		next = props.cursorURL(props.Next)
+	case props.Query.Page < Pages(props.Total, props.Query.PerPage):
+		next = props.pageURL(props.Query.Page + 1)
+	default:
+		return ""
+	}
+
+	return elements.A(elements.AProps{
+		GlobalProps: elements.GlobalProps{
+			Class: []string{"load-more"},
+			Htmx:  props.htmx(next, false),
+		},
+		Href:      html.EscapeString(next),
+		Rel:       []func() aRelOption{relNext},
+		InnerHTML: html.EscapeString(props.MoreText),
+	})
+}
+
+type aRelOption = elements.ARelOption
+
+var (
+	relNext = elements.AOptions.Rel.Next
+	relPrev = elements.AOptions.Rel.Prev
+)
+
+// link renders prev or next, disabled when there is no such page
+func (props Props) link(u string, enabled bool, text string, rel func() aRelOption) string {
+	if !enabled {
+		return elements.A(elements.AProps{
+			GlobalProps: elements.GlobalProps{Aria: utils.AriaRoles{"disabled": "true"}},
+			InnerHTML:   html.EscapeString(text),
+		})
+	}
+	return elements.A(elements.AProps{
+		GlobalProps: elements.GlobalProps{Htmx: props.htmx(u, true)},
+		Href:        html.EscapeString(u),
+		Rel:         []func() aRelOption{rel},
+		InnerHTML:   html.EscapeString(text),
+	})
+}
+
+func (props Props) htmx(u string, push bool) utils.HtmxProps {
+	if props.Target == "" {
+		return nil
+	}
+	hx := utils.HtmxProps{
+		"get":    html.EscapeString(u),
+		"target": html.EscapeString(props.Target),
+	}
+	if push {
+		hx["push-url"] = "true"
+	}
+	if props.Swap != "" {
+		hx["swap"] = html.EscapeString(props.Swap)
+	}
+	if props.Select != "" {
+		hx["select"] = html.EscapeString(props.Select)
+	}
+	return hx
+}
+
+// pageURL links page n, leaving the parameter out for the first page
+func (props Props) pageURL(n int) string {
+	values := props.values()
+	values.Del(props.Params.CursorParam)
+	if n > 1 {
+		values.Set(props.Params.PageParam, strconv.Itoa(n))
+	}
+	return props.url(values)
+}
+
+func (props Props) cursorURL(cursor string) string {
+	values := props.values()
+	values.Del(props.Params.PageParam)
+	values.Set(props.Params.CursorParam, cursor)
+	return props.url(values)
+}
+
+// values copies the kept query without the pagination parameters
+func (props Props) values() url.Values {
+	values := url.Values{}
+	for k, v := range props.Values {
+		values[k] = append([]string(nil), v...)
+	}
+	values.Del(props.Params.PageParam)
+	values.Del(props.Params.CursorParam)
+	return values
+}
+
+func (props Props) url(values url.Values) string {
+	if len(values) == 0 {
+		return props.URL
+	}
+	return props.URL + "?" + values.Encode()
+}
+
+type paginationOptions struct {
+	Style paginationStyleOptions
+}
+
+var PaginationOptions paginationOptions
+
+/* Style */
+type paginationStyleOption struct{ string }
+
+func (o paginationStyleOption) String() string { return o.string }
+
+func paginationStyleOptionNumbered() paginationStyleOption {
+	return paginationStyleOption{"numbered"}
+}
+
+func paginationStyleOptionCursor() paginationStyleOption {
+	return paginationStyleOption{"cursor"}
+}
+
+func paginationStyleOptionLoadMore() paginationStyleOption {
+	return paginationStyleOption{"load-more"}
+}
+
+type paginationStyleOptions struct {
+	Numbered func() paginationStyleOption
+	Cursor   func() paginationStyleOption
+	LoadMore func() paginationStyleOption
+}
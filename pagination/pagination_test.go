@@ -0,0 +1,100 @@
+package pagination
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		page, pages int
+		want        string
+	}{
+		{7, 20, "[1 0 5 6 7 8 9 0 20]"},
+		{1, 20, "[1 2 3 0 20]"},
+		{4, 7, "[1 2 3 4 5 6 7]"},
+		{1, 1, "[1]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(Window(tt.page, tt.pages, 2)); got != tt.want {
+			t.Errorf("Window(%d, %d, 2) = %s, want %s", tt.page, tt.pages, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	q := ParseValues(url.Values{"page": {"3"}, "per_page": {"500"}}, QueryOptions{})
+	if q.Page != 3 || q.PerPage != DefaultMaxPerPage || q.Offset() != 200 {
+		t.Fatalf("unexpected %+v", q)
+	}
+	if q := ParseValues(url.Values{"page": {"-1"}}, QueryOptions{}); q.Page != 1 || q.Offset() != 0 {
+		t.Fatalf("unexpected %+v", q)
+	}
+}
+
+func TestParseHugePage(t *testing.T) {
+	q := ParseValues(url.Values{"page": {"9223372036854775807"}, "per_page": {"100"}}, QueryOptions{})
+	if q.Offset() < 0 || q.Offset() > math.MaxInt-q.PerPage {
+		t.Fatalf("offset overflowed: %+v %d", q, q.Offset())
+	}
+}
+
+// Collapses the builders' padding to single spaces
+func squash(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestPagination(t *testing.T) {
+	got := squash(Pagination(Props{
+		URL:    "/articles",
+		Values: url.Values{"q": {"go"}, "page": {"3"}},
+		Query:  Query{Page: 3, PerPage: 10},
+		Total:  95,
+		Target: "#articles",
+	}))
+
+	for _, want := range []string{
+		`aria-label="Pagination"`,
+		`class="pagination"`,
+		`hx-get="/articles?page=2&amp;q=go" hx-push-url="true" hx-target="#articles"`,
+		`href="/articles?page=2&amp;q=go" rel="prev"`,
+		`href="/articles?page=4&amp;q=go" rel="next"`,
+		`aria-current="page"`,
+		`href="/articles?q=go"`,
+		`<span class="gap" aria-hidden="true" >…</span>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	if strings.Count(got, "aria-current") != 1 || !strings.Contains(got, `aria-current="page" hx-get="/articles?page=3&amp;q=go"`) {
+		t.Errorf("current page not marked once: %s", got)
+	}
+}
+
+func TestPaginationEdges(t *testing.T) {
+	first := squash(Pagination(Props{URL: "/a", Query: Query{Page: 1, PerPage: 10}, Total: 30}))
+	if !strings.Contains(first, `aria-disabled="true" >Previous`) || strings.Contains(first, "hx-") {
+		t.Errorf("first page: %s", first)
+	}
+
+	// Past the last page, previous leads back to it and next is disabled
+	past := squash(Pagination(Props{URL: "/a", Query: Query{Page: 9, PerPage: 10}, Total: 30}))
+	if !strings.Contains(past, `href="/a?page=3" rel="prev"`) || !strings.Contains(past, `aria-disabled="true" >Next`) {
+		t.Errorf("past the end: %s", past)
+	}
+	if strings.Contains(past, "aria-current") {
+		t.Errorf("marked a page past the end current: %s", past)
+	}
+
+	more := squash(Pagination(Props{URL: "/a", Style: PaginationOptions.Style.LoadMore, Next: "abc", Query: Query{PerPage: 10}}))
+	if !strings.Contains(more, `hx-get="/a?cursor=abc"`) || !strings.Contains(more, `hx-swap="outerHTML" hx-target="closest nav"`) || strings.Contains(more, "push-url") {
+		t.Errorf("load more: %s", more)
+	}
+	if Pagination(Props{Style: PaginationOptions.Style.LoadMore, Query: Query{PerPage: 10}}) != "" {
+		t.Error("load more after the last page")
+	}
+}
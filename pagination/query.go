@@ -0,0 +1,154 @@
+package pagination
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultPerPage    = 20
+	DefaultMaxPerPage = 100
+)
+
+/*
+ * QueryOptions name the query parameters of a listing and bound its page
+ * size. Zero values take the defaults.
+ */
+type QueryOptions struct {
+	// "page"
+	PageParam string
+	// "cursor"
+	CursorParam string
+	// "per_page", fixed at PerPage when "-"
+	PerPageParam string
+	// DefaultPerPage
+	PerPage int
+	// DefaultMaxPerPage
+	MaxPerPage int
+}
+
+func (o QueryOptions) withDefaults() QueryOptions {
+	if o.PageParam == "" {
+		o.PageParam = "page"
+	}
+	if o.CursorParam == "" {
+		o.CursorParam = "cursor"
+	}
+	if o.PerPageParam == "" {
+		o.PerPageParam = "per_page"
+	}
+	if o.PerPage <= 0 {
+		o.PerPage = DefaultPerPage
+	}
+	if o.MaxPerPage <= 0 {
+		o.MaxPerPage = DefaultMaxPerPage
+	}
+	return o
+}
+
+/*
+ * The page a request asks for. Page is 1-based and always valid, small
+ * enough that Offset cannot overflow; Cursor is opaque, for cursor-based
+ * listings.
+ */
+type Query struct {
+	Page    int
+	PerPage int
+	Cursor  string
+}
+
+// Parse reads the request's page, page size and cursor
+func Parse(r *http.Request, opts QueryOptions) Query {
+	return ParseValues(r.URL.Query(), opts)
+}
+
+// ParseValues is Parse for values already parsed
+func ParseValues(values url.Values, opts QueryOptions) Query {
+	opts = opts.withDefaults()
+	q := Query{Page: 1, PerPage: opts.PerPage, Cursor: values.Get(opts.CursorParam)}
+
+	if n, err := strconv.Atoi(values.Get(opts.PageParam)); err == nil && n > 1 {
+		q.Page = n
+	}
+	if opts.PerPageParam != "-" {
+		if n, err := strconv.Atoi(values.Get(opts.PerPageParam)); err == nil && n > 0 {
+			q.PerPage = n
+		}
+	}
+	if q.PerPage > opts.MaxPerPage {
+		q.PerPage = opts.MaxPerPage
+	}
+	if last := math.MaxInt / q.PerPage; q.Page > last {
+		q.Page = last
+	}
+
+	return q
+}
+
+/*
+ * Offset of the page's first item, for LIMIT/OFFSET queries. Cursor-based
+ * listings query PerPage+1 items after the cursor instead, the extra one
+ * telling whether there is a next page.
+ */
+func (q Query) Offset() int {
+	return (q.Page - 1) * q.PerPage
+}
+
+// Pages counts the pages of total items
+func Pages(total, perPage int) int {
+	if total <= 0 || perPage <= 0 {
+		return 1
+	}
+	return (total + perPage - 1) / perPage
+}
+
+/*
+ * Window lists the page numbers to link around page: the first and last
+ * pages and size pages either side of the current one, with 0 for each gap.
+ *
+ *	Window(7, 20, 2) // [1 0 5 6 7 8 9 0 20]
+ */
+func Window(page, pages, size int) []int {
+	if pages < 1 {
+		return nil
+	}
+	if page < 1 {
+		page = 1
+	}
+	if page > pages {
+		page = pages
+	}
+
+	lo, hi := page-size, page+size
+	if lo < 1 {
+		lo = 1
+	}
+	if hi > pages {
+		hi = pages
+	}
+
+	var w []int
+	if lo > 1 {
+		w = append(w, 1)
+		// A gap of one page shows the page instead
+		if lo == 3 {
+			w = append(w, 2)
+		} else if lo > 3 {
+			w = append(w, 0)
+		}
+	}
+	for n := lo; n <= hi; n++ {
+		w = append(w, n)
+	}
+	if hi < pages {
+		if hi == pages-2 {
+			w = append(w, pages-1)
+		} else if hi < pages-2 {
+			w = append(w, 0)
+		}
+		w = append(w, pages)
+	}
+	return w
+}
@@ -0,0 +1,245 @@
+/*
+ * Package render carries request-scoped data through rendering, so a
+ * deeply nested component can read the locale, user, CSP nonce, CSRF
+ * token or base path without every props struct passing them down.
+ * Middleware fills the context from the request; components read it with
+ * the accessors, or with their own typed keys.
+ *
+ *	handler = render.Middleware(handler, render.Options{
+ *		User: func(r *http.Request) interface{} { return session.User(r) },
+ *	})
+ *	handler = security.CSRF(security.Nonces(handler, security.NonceOptions{}), csrfOptions)
+ *
+ *	func avatar(ctx context.Context) string {
+ *		u, _ := render.User(ctx).(*User)
+ *		return Img(ImgProps{Src: render.URL(ctx, "/avatars/"+u.ID)})
+ *	}
+ */
+package render
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bitpartio/Mx/security"
+)
+
+/*
+ * Key is a typed context key. Keys are compared by identity, so two keys
+ * with the same name never collide.
+ *
+ *	var Theme = render.NewKey[string]("theme")
+ *	ctx = Theme.With(ctx, "dark")
+ *	theme := Theme.Value(ctx)
+ */
+type Key[T any] struct {
+	name *string
+}
+
+// NewKey makes a key; name only shows in debugging output
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: &name}
+}
+
+func (k Key[T]) String() string {
+	if k.name == nil {
+		return "render.Key"
+	}
+	return "render.Key(" + *k.name + ")"
+}
+
+// With returns a copy of ctx holding v under the key
+func (k Key[T]) With(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// Get returns the key's value and whether ctx holds one
+func (k Key[T]) Get(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// Value returns the key's value, the zero value when unset
+func (k Key[T]) Value(ctx context.Context) T {
+	v, _ := k.Get(ctx)
+	return v
+}
+
+var (
+	requestKey  = NewKey[*http.Request]("request")
+	localeKey   = NewKey[string]("locale")
+	userKey     = NewKey[interface{}]("user")
+	basePathKey = NewKey[string]("base path")
+)
+
+// Request returns the request being rendered, nil outside Middleware
+func Request(ctx context.Context) *http.Request {
+	return requestKey.Value(ctx)
+}
+
+// Locale returns the BCP 47 language tag to render in, e.g. "en-GB"
+func Locale(ctx context.Context) string {
+	return localeKey.Value(ctx)
+}
+
+// WithLocale returns a copy of ctx rendering in locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return localeKey.With(ctx, locale)
+}
+
+// User returns the signed-in user as Options.User found it, nil if none
+func User(ctx context.Context) interface{} {
+	return userKey.Value(ctx)
+}
+
+// WithUser returns a copy of ctx with user signed in
+func WithUser(ctx context.Context, user interface{}) context.Context {
+	return userKey.With(ctx, user)
+}
+
+// BasePath returns the path the site is mounted under, "" at the root
+func BasePath(ctx context.Context) string {
+	return basePathKey.Value(ctx)
+}
+
+// WithBasePath returns a copy of ctx with the site mounted under path
+func WithBasePath(ctx context.Context, path string) context.Context {
+	return basePathKey.With(ctx, strings.TrimSuffix(path, "/"))
+}
+
+// URL prefixes a site-absolute path with the base path
+func URL(ctx context.Context, path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return path
+	}
+	return BasePath(ctx) + path
+}
+
+// Nonce returns the CSP nonce security.Nonces set for the request
+func Nonce(ctx context.Context) string {
+	return security.Nonce(ctx)
+}
+
+// CSRFToken returns the token security.CSRF set for the request
+func CSRFToken(ctx context.Context) string {
+	return security.CSRFToken(ctx)
+}
+
+/*
+ * Options
+ */
+type Options struct {
+	// Picks the request's locale; the preferred Accept-Language tag, or
+	// DefaultLocale, when nil. Anything but a well-formed language tag
+	// falls back to DefaultLocale. Set Vary for what it reads.
+	Locale func(r *http.Request) string
+	// "en" when empty
+	DefaultLocale string
+	// Finds the signed-in user, nil for none
+	User func(r *http.Request) interface{}
+	// Path the site is mounted under, e.g. "/app"
+	BasePath string
+	// Take the base path from the X-Forwarded-Prefix header set by a
+	// trusted proxy, falling back to BasePath
+	TrustForwardedPrefix bool
+}
+
+/*
+ * Middleware fills the render context from each request. The CSP nonce
+ * and CSRF token come from the security middleware, so place this one
+ * inside them.
+ */
+func Middleware(next http.Handler, opts Options) http.Handler {
+	if opts.DefaultLocale == "" {
+		opts.DefaultLocale = "en"
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestKey.With(r.Context(), r)
+
+		locale := ""
+		if opts.Locale != nil {
+			locale = opts.Locale(r)
+		} else {
+			w.Header().Add("Vary", "Accept-Language")
+			locale = firstLanguage(r.Header.Get("Accept-Language"))
+		}
+		// The locale ends up in markup such as <html lang>, so only ever
+		// a tag, never what a client made up
+		if !validTag(locale) {
+			locale = opts.DefaultLocale
+		}
+		ctx = WithLocale(ctx, locale)
+
+		if opts.User != nil {
+			if u := opts.User(r); u != nil {
+				ctx = WithUser(ctx, u)
+			}
+		}
+
+		base := opts.BasePath
+		if opts.TrustForwardedPrefix {
+			if prefix := r.Header.Get("X-Forwarded-Prefix"); strings.HasPrefix(prefix, "/") {
+				base = prefix
+			}
+		}
+		ctx = WithBasePath(ctx, base)
+
+		r = r.WithContext(ctx)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// firstLanguage returns the Accept-Language tag with the highest weight,
+// the earliest on ties
+func firstLanguage(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if !validTag(tag) {
+			continue
+		}
+
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			if f, err := strconv.ParseFloat(params[2:], 64); err == nil {
+				q = f
+			}
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+/*
+ * validTag reports whether tag is shaped like a BCP 47 language tag:
+ * subtags of one to eight letters or digits joined by hyphens, starting
+ * with a letter, 35 characters at most. Whether the language exists is
+ * left to the i18n package.
+ */
+func validTag(tag string) bool {
+	if len(tag) == 0 || len(tag) > 35 {
+		return false
+	}
+	if c := tag[0]; !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+		return false
+	}
+
+	for _, sub := range strings.Split(tag, "-") {
+		if len(sub) == 0 || len(sub) > 8 {
+			return false
+		}
+		for i := 0; i < len(sub); i++ {
+			c := sub[i]
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var ctx context.Context
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}), Options{
+		BasePath: "/app/",
+		User:     func(r *http.Request) interface{} { return "ada" },
+	})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "fr;q=0.5, de-CH, *;q=0.1")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if Locale(ctx) != "de-CH" || User(ctx) != "ada" || Request(ctx) == nil {
+		t.Fatalf("unexpected locale %q, user %v", Locale(ctx), User(ctx))
+	}
+	if got := URL(ctx, "/img/a.png"); got != "/app/img/a.png" {
+		t.Fatalf("URL gave %q", got)
+	}
+	if got := URL(ctx, "https://example.com/"); got != "https://example.com/" {
+		t.Fatalf("URL gave %q", got)
+	}
+}
+
+func TestKey(t *testing.T) {
+	a, b := NewKey[string]("theme"), NewKey[string]("theme")
+	ctx := a.With(context.Background(), "dark")
+	if a.Value(ctx) != "dark" {
+		t.Fatal("value lost")
+	}
+	if _, ok := b.Get(ctx); ok {
+		t.Fatal("keys with the same name collided")
+	}
+}
+
+func TestHostileLocale(t *testing.T) {
+	var locale string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale = Locale(r.Context())
+	})
+
+	for header, want := range map[string]string{
+		`"><script>alert(1)</script>`:             "en",
+		`x"onmouseover="alert(1), fr;q=0.5`:       "fr",
+		"en-US-" + strings.Repeat("abcdefgh-", 4): "en",
+		"de--CH, 1a": "en",
+		"zh-Hant-TW": "zh-Hant-TW",
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", header)
+		w := httptest.NewRecorder()
+		Middleware(next, Options{}).ServeHTTP(w, r)
+
+		if locale != want {
+			t.Errorf("%q: got %q, want %q", header, locale, want)
+		}
+		if w.Header().Get("Vary") != "Accept-Language" {
+			t.Errorf("%q: Vary %q", header, w.Header().Get("Vary"))
+		}
+	}
+
+	custom := Middleware(next, Options{
+		DefaultLocale: "de",
+		Locale:        func(r *http.Request) string { return r.URL.Query().Get("lang") },
+	})
+	custom.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/?lang=%3Cb%3E", nil))
+	if locale != "de" {
+		t.Errorf("custom Locale gave %q", locale)
+	}
+}
@@ -0,0 +1,395 @@
+package security
+
+// Ref: https://cheatsheetseries.owasp.org/cheatsheets/Cross-Site_Request_Forgery_Prevention_Cheat_Sheet.html
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/utils"
+)
+
+const (
+	DefaultCSRFCookie = "_csrf"
+	DefaultCSRFField  = "csrf_token"
+	DefaultCSRFHeader = "X-CSRF-Token"
+)
+
+var (
+	ErrCSRFToken  = errors.New("security: missing or invalid CSRF token")
+	ErrCSRFOrigin = errors.New("security: cross-origin request")
+)
+
+type csrfKey struct{}
+
+type csrfToken struct {
+	token   string
+	field   string
+	header  string
+	host    string
+	trusted []string
+	// Marks the response as embedding the token
+	vary func()
+}
+
+// requestToken returns the request's token, marking the response as
+// varying by the cookie the token is tied to
+func requestToken(ctx context.Context) (csrfToken, bool) {
+	t, ok := ctx.Value(csrfKey{}).(csrfToken)
+	if ok {
+		t.vary()
+	}
+	return t, ok
+}
+
+/*
+ * CSRFToken returns the request's CSRF token, "" outside CSRF. A page
+ * embedding it is only valid with the visitor's cookie, so reading it adds
+ * Vary: Cookie to the response, as do the builders below.
+ */
+func CSRFToken(ctx context.Context) string {
+	t, _ := requestToken(ctx)
+	return t.token
+}
+
+/*
+ * Form is elements.Form with a hidden input carrying the request's CSRF
+ * token when it posts to this site or a trusted origin. Forms posting
+ * anywhere else, or with GET, never get the token.
+ */
+func Form(ctx context.Context, props elements.FormProps) string {
+	t, ok := ctx.Value(csrfKey{}).(csrfToken)
+	if ok && props.Method != nil && props.Method().String() == "post" && localAction(props.Action, t.host, t.trusted) {
+		t.vary()
+		props.InnerHTML = csrfInput(t) + props.InnerHTML
+	}
+	return elements.Form(props)
+}
+
+/*
+ * Body is elements.Body sending the request's CSRF token with every htmx
+ * request from the page, added to the JSON object of any hx-headers it
+ * already has. A value other than a JSON object, such as a js:
+ * expression, cannot be merged and is kept without the token; build it
+ * with CSRFHeaders instead.
+ */
+func Body(ctx context.Context, props elements.BodyProps) string {
+	t, ok := ctx.Value(csrfKey{}).(csrfToken)
+	if !ok {
+		return elements.Body(props)
+	}
+	if headers, ok := mergeHeaders(props.Htmx["headers"], t); ok {
+		t.vary()
+		props.Htmx = utils.With(props.Htmx, "headers", headers)
+	}
+	return elements.Body(props)
+}
+
+/*
+ * CSRFField renders the hidden input carrying the request's CSRF token,
+ * for forms not built with Form.
+ */
+func CSRFField(ctx context.Context) string {
+	t, ok := requestToken(ctx)
+	if !ok {
+		return ""
+	}
+	return csrfInput(t)
+}
+
+/*
+ * CSRFHeaders returns the attribute-escaped hx-headers value sending the
+ * request's CSRF token with htmx requests:
+ *
+ *	GlobalProps{Htmx: utils.HtmxProps{"headers": security.CSRFHeaders(ctx)}}
+ */
+func CSRFHeaders(ctx context.Context) string {
+	t, ok := requestToken(ctx)
+	if !ok {
+		return ""
+	}
+	return csrfHeaders(t)
+}
+
+// CSRFMeta renders <meta name="csrf-token">, for scripts making requests
+func CSRFMeta(ctx context.Context) string {
+	token := CSRFToken(ctx)
+	if token == "" {
+		return ""
+	}
+	return elements.Meta(elements.MetaProps{Name: "csrf-token", Content: token})
+}
+
+/*
+ * CSRFOptions
+ */
+type CSRFOptions struct {
+	// HMAC key signing tokens, at least 32 random bytes, shared by every
+	// instance serving the site
+	Key []byte
+	// DefaultCSRFCookie when empty
+	Cookie string
+	// Form field carrying the token, DefaultCSRFField when empty
+	Field string
+	// Header carrying the token, DefaultCSRFHeader when empty
+	Header string
+	// Cookie path, "/" when empty
+	Path string
+	// Cookie lifetime, a browser session when zero
+	MaxAge time.Duration
+	// Send the cookie over plain HTTP too, for local development
+	Insecure bool
+	// Origins besides the request's own allowed to send unsafe requests,
+	// e.g. "https://app.example.com"
+	TrustedOrigins []string
+	// Requests exempt from the check, such as webhooks
+	Exempt func(r *http.Request) bool
+	// Responds to rejected requests, 403 Forbidden when nil. The reason is
+	// available from CSRFError.
+	ErrorHandler http.Handler
+}
+
+type csrfErrorKey struct{}
+
+// CSRFError returns why CSRF rejected the request, for ErrorHandler
+func CSRFError(r *http.Request) error {
+	err, _ := r.Context().Value(csrfErrorKey{}).(error)
+	return err
+}
+
+/*
+ * CSRF protects unsafe-method requests with signed double-submit tokens.
+ * Each browser gets a random secret in an HttpOnly cookie; every response
+ * carries a fresh token, a random salt signed with Key together with the
+ * secret, which the next unsafe request must return in the form field or
+ * header. Subdomains able to set cookies cannot forge tokens without Key,
+ * and fresh salts keep tokens out of reach of compression attacks.
+ *
+ * The token is in the request context. Build forms with Form and <body>
+ * with Body, passing the request context, and the token is added to every
+ * form posting to this site or a trusted origin and to the hx-headers htmx
+ * sends with its requests:
+ *
+ *	security.Body(ctx, BodyProps{InnerHTML: security.Form(ctx, FormProps{
+ *		Method: FormOptions.Method.Post, Action: "/save", InnerHTML: fields,
+ *	})})
+ *
+ * Responses are passed through untouched, so streaming, SSE and websocket
+ * handlers behind CSRF work as usual. Unsafe requests with a foreign
+ * Origin are rejected before the token is checked.
+ */
+func CSRF(next http.Handler, opts CSRFOptions) http.Handler {
+	if len(opts.Key) < 32 {
+		panic("security: CSRF needs a Key of at least 32 bytes")
+	}
+	if opts.Cookie == "" {
+		opts.Cookie = DefaultCSRFCookie
+	}
+	if opts.Field == "" {
+		opts.Field = DefaultCSRFField
+	}
+	if opts.Header == "" {
+		opts.Header = DefaultCSRFHeader
+	}
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := csrfSecret(r, opts.Cookie)
+		if secret == nil {
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     opts.Cookie,
+				Value:    base64.RawURLEncoding.EncodeToString(secret),
+				Path:     opts.Path,
+				MaxAge:   int(opts.MaxAge / time.Second),
+				Secure:   !opts.Insecure,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+
+		if !safeMethod(r.Method) && (opts.Exempt == nil || !opts.Exempt(r)) {
+			err := checkCSRF(r, secret, opts)
+			if err != nil {
+				r = r.WithContext(context.WithValue(r.Context(), csrfErrorKey{}, err))
+				opts.ErrorHandler.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		token, err := signCSRF(opts.Key, secret)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		var once sync.Once
+		t := csrfToken{
+			token:   token,
+			field:   opts.Field,
+			header:  opts.Header,
+			host:    r.Host,
+			trusted: opts.TrustedOrigins,
+			vary: func() {
+				once.Do(func() { w.Header().Add("Vary", "Cookie") })
+			},
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfKey{}, t)))
+	})
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// csrfSecret reads the cookie secret, nil when missing or malformed
+func csrfSecret(r *http.Request, name string) []byte {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(c.Value)
+	if err != nil || len(secret) != 32 {
+		return nil
+	}
+	return secret
+}
+
+func checkCSRF(r *http.Request, secret []byte, opts CSRFOptions) error {
+	if !sameOrigin(r, opts.TrustedOrigins) {
+		return ErrCSRFOrigin
+	}
+
+	token := r.Header.Get(opts.Header)
+	if token == "" {
+		token = r.PostFormValue(opts.Field)
+	}
+	if !verifyCSRF(opts.Key, secret, token) {
+		return ErrCSRFToken
+	}
+	return nil
+}
+
+// sameOrigin checks Origin, or Referer when browsers omit it
+func sameOrigin(r *http.Request, trusted []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		if ref, err := url.Parse(r.Referer()); err == nil && ref.Host != "" {
+			origin = ref.Scheme + "://" + ref.Host
+		}
+	}
+	if origin == "" {
+		// Not a browser, or one stripping both; the token decides
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return ownOrigin(u, r.Host, trusted)
+}
+
+// ownOrigin reports whether u is on host or one of the trusted origins
+func ownOrigin(u *url.URL, host string, trusted []string) bool {
+	if strings.EqualFold(u.Host, host) {
+		return true
+	}
+	origin := u.Scheme + "://" + u.Host
+	for _, o := range trusted {
+		if strings.EqualFold(strings.TrimSuffix(o, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// localAction reports whether a form action submits to this site: empty,
+// relative, or absolute on host or a trusted origin
+func localAction(action, host string, trusted []string) bool {
+	u, err := url.Parse(strings.TrimSpace(action))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" && u.Host == "" {
+		return true
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	return ownOrigin(u, host, trusted)
+}
+
+// A token is base64url(salt || HMAC-SHA256(key, secret || salt))
+func signCSRF(key, secret []byte) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(append(salt, csrfMAC(key, secret, salt)...)), nil
+}
+
+func verifyCSRF(key, secret []byte, token string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != 16+sha256.Size {
+		return false
+	}
+	return hmac.Equal(b[16:], csrfMAC(key, secret, b[:16]))
+}
+
+func csrfMAC(key, secret, salt []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(secret)
+	mac.Write(salt)
+	return mac.Sum(nil)
+}
+
+func csrfInput(t csrfToken) string {
+	return elements.InputHidden(elements.InputHiddenProps{Name: t.field, Value: t.token})
+}
+
+func csrfHeaders(t csrfToken) string {
+	b, _ := json.Marshal(map[string]string{t.header: t.token})
+	return html.EscapeString(string(b))
+}
+
+// mergeHeaders adds the token to an attribute-escaped hx-headers JSON
+// object, false when the value is something else
+func mergeHeaders(value string, t csrfToken) (string, bool) {
+	if value == "" {
+		return csrfHeaders(t), true
+	}
+	var headers map[string]interface{}
+	if err := json.Unmarshal([]byte(html.UnescapeString(value)), &headers); err != nil || headers == nil {
+		return "", false
+	}
+	headers[t.header] = t.token
+	b, _ := json.Marshal(headers)
+	return html.EscapeString(string(b)), true
+}
@@ -0,0 +1,264 @@
+package security
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers#security
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitpartio/Mx/elements"
+)
+
+func init() {
+	HeaderOptions = headerOptions{
+		CrossOriginOpenerPolicy: coopOptions{
+			UnsafeNone:            coopOptionUnsafeNone,
+			SameOriginAllowPopups: coopOptionSameOriginAllowPopups,
+			SameOrigin:            coopOptionSameOrigin,
+		},
+		CrossOriginEmbedderPolicy: coepOptions{
+			UnsafeNone:     coepOptionUnsafeNone,
+			RequireCorp:    coepOptionRequireCorp,
+			Credentialless: coepOptionCredentialless,
+		},
+		CrossOriginResourcePolicy: corpOptions{
+			SameSite:    corpOptionSameSite,
+			SameOrigin:  corpOptionSameOrigin,
+			CrossOrigin: corpOptionCrossOrigin,
+		},
+	}
+}
+
+/*
+ * Strict-Transport-Security
+ */
+type HSTS struct {
+	MaxAge            time.Duration
+	IncludeSubDomains bool
+	Preload           bool
+}
+
+func (h HSTS) String() string {
+	s := "max-age=" + strconv.FormatInt(int64(h.MaxAge/time.Second), 10)
+	if h.IncludeSubDomains {
+		s += "; includeSubDomains"
+	}
+	if h.Preload {
+		s += "; preload"
+	}
+	return s
+}
+
+/*
+ * Permissions-Policy, mapping features such as "camera" or "geolocation"
+ * to their allowlist. An empty allowlist disables the feature, "self" and
+ * "*" are keywords and anything else is an origin.
+ */
+type PermissionsPolicy map[string][]string
+
+func (p PermissionsPolicy) String() string {
+	features := make([]string, 0, len(p))
+	for feature := range p {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+
+	d := make([]string, len(features))
+	for i, feature := range features {
+		allow := p[feature]
+		if len(allow) == 1 && allow[0] == "*" {
+			d[i] = feature + "=*"
+			continue
+		}
+
+		list := make([]string, len(allow))
+		for j, origin := range allow {
+			if origin == "self" || origin == "*" {
+				list[j] = origin
+			} else {
+				list[j] = strconv.Quote(origin)
+			}
+		}
+		d[i] = feature + "=(" + strings.Join(list, " ") + ")"
+	}
+
+	return strings.Join(d, ", ")
+}
+
+/*
+ * Headers collects the security response headers an Mx app sends. Unset
+ * fields send nothing.
+ */
+type Headers struct {
+	CSP *CSP
+	// Generate a nonce per request, see Nonces
+	CSPNonce bool
+	// Send Content-Security-Policy-Report-Only instead of enforcing
+	CSPReportOnly bool
+
+	HSTS    *HSTS
+	NoSniff bool
+	// Same values as the elements' Referrerpolicy attributes, e.g.
+	// elements.ReferrerpolicyOptions.StrictCrossOrigin
+	Referrerpolicy            func() elements.ReferrerpolicyOption
+	PermissionsPolicy         PermissionsPolicy
+	CrossOriginOpenerPolicy   func() coopOption
+	CrossOriginEmbedderPolicy func() coepOption
+	CrossOriginResourcePolicy func() corpOption
+}
+
+// Recommended baseline, with a nonce-based DefaultCSP
+func DefaultHeaders() Headers {
+	csp := DefaultCSP
+	return Headers{
+		CSP:      &csp,
+		CSPNonce: true,
+		HSTS: &HSTS{
+			MaxAge:            2 * 365 * 24 * time.Hour,
+			IncludeSubDomains: true,
+		},
+		NoSniff:        true,
+		Referrerpolicy: elements.ReferrerpolicyOptions.StrictCrossOrigin,
+		PermissionsPolicy: PermissionsPolicy{
+			"camera":      {},
+			"geolocation": {},
+			"microphone":  {},
+		},
+		CrossOriginOpenerPolicy:   HeaderOptions.CrossOriginOpenerPolicy.SameOrigin,
+		CrossOriginResourcePolicy: HeaderOptions.CrossOriginResourcePolicy.SameOrigin,
+	}
+}
+
+// Apply sets every configured header except a nonce-based CSP, which
+// needs a request (see Handler)
+func (h Headers) Apply(header http.Header) {
+	if h.CSP != nil && !h.CSPNonce {
+		header.Set(h.cspHeader(), h.CSP.String())
+	}
+	if h.HSTS != nil {
+		header.Set("Strict-Transport-Security", h.HSTS.String())
+	}
+	if h.NoSniff {
+		header.Set("X-Content-Type-Options", "nosniff")
+	}
+	if h.Referrerpolicy != nil {
+		header.Set("Referrer-Policy", h.Referrerpolicy().String())
+	}
+	if h.PermissionsPolicy != nil {
+		header.Set("Permissions-Policy", h.PermissionsPolicy.String())
+	}
+	if h.CrossOriginOpenerPolicy != nil {
+		header.Set("Cross-Origin-Opener-Policy", h.CrossOriginOpenerPolicy().String())
+	}
+	if h.CrossOriginEmbedderPolicy != nil {
+		header.Set("Cross-Origin-Embedder-Policy", h.CrossOriginEmbedderPolicy().String())
+	}
+	if h.CrossOriginResourcePolicy != nil {
+		header.Set("Cross-Origin-Resource-Policy", h.CrossOriginResourcePolicy().String())
+	}
+}
+
+// Handler sends the headers on every response
+func (h Headers) Handler(next http.Handler) http.Handler {
+	if h.CSP != nil && h.CSPNonce {
+		next = Nonces(next, NonceOptions{
+			Policy:     h.CSP.NoncePolicy,
+			ReportOnly: h.CSPReportOnly,
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Apply(w.Header())
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h Headers) cspHeader() string {
+	if h.CSPReportOnly {
+		return "Content-Security-Policy-Report-Only"
+	}
+	return "Content-Security-Policy"
+}
+
+/*
+ * Options
+ */
+type headerOptions struct {
+	CrossOriginOpenerPolicy   coopOptions
+	CrossOriginEmbedderPolicy coepOptions
+	CrossOriginResourcePolicy corpOptions
+}
+
+var HeaderOptions headerOptions
+
+/* Cross-Origin-Opener-Policy */
+type coopOption struct{ string }
+
+func (o coopOption) String() string { return o.string }
+
+func coopOptionUnsafeNone() coopOption {
+	return coopOption{"unsafe-none"}
+}
+
+func coopOptionSameOriginAllowPopups() coopOption {
+	return coopOption{"same-origin-allow-popups"}
+}
+
+func coopOptionSameOrigin() coopOption {
+	return coopOption{"same-origin"}
+}
+
+type coopOptions struct {
+	UnsafeNone            func() coopOption
+	SameOriginAllowPopups func() coopOption
+	SameOrigin            func() coopOption
+}
+
+/* Cross-Origin-Embedder-Policy */
+type coepOption struct{ string }
+
+func (o coepOption) String() string { return o.string }
+
+func coepOptionUnsafeNone() coepOption {
+	return coepOption{"unsafe-none"}
+}
+
+func coepOptionRequireCorp() coepOption {
+	return coepOption{"require-corp"}
+}
+
+func coepOptionCredentialless() coepOption {
+	return coepOption{"credentialless"}
+}
+
+type coepOptions struct {
+	UnsafeNone     func() coepOption
+	RequireCorp    func() coepOption
+	Credentialless func() coepOption
+}
+
+/* Cross-Origin-Resource-Policy */
+type corpOption struct{ string }
+
+func (o corpOption) String() string { return o.string }
+
+func corpOptionSameSite() corpOption {
+	return corpOption{"same-site"}
+}
+
+func corpOptionSameOrigin() corpOption {
+	return corpOption{"same-origin"}
+}
+
+func corpOptionCrossOrigin() corpOption {
+	return corpOption{"cross-origin"}
+}
+
+type corpOptions struct {
+	SameSite    func() corpOption
+	SameOrigin  func() corpOption
+	CrossOrigin func() corpOption
+}
@@ -0,0 +1,309 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitpartio/Mx/elements"
+)
+
+func TestCSP(t *testing.T) {
+	p := CSP{
+		DefaultSrc:              []Source{Self},
+		ImgSrc:                  []Source{Self, Data, Host("cdn.example.com")},
+		ObjectSrc:               []Source{None},
+		Sandbox:                 []string{},
+		UpgradeInsecureRequests: true,
+		ReportURI:               "/csp",
+	}
+	want := "default-src 'self'; img-src 'self' data: cdn.example.com; object-src 'none'; sandbox; upgrade-insecure-requests; report-uri /csp"
+	if got := p.String(); got != want {
+		t.Errorf("got %q", got)
+	}
+
+	if got := SHA256("alert(1)"); got != "'sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI='" {
+		t.Errorf("hash %q", got)
+	}
+}
+
+func TestCSPWithNonce(t *testing.T) {
+	got := CSP{DefaultSrc: []Source{None}, StyleSrc: []Source{Self}}.NoncePolicy("abc")
+	want := "default-src 'none'; script-src 'nonce-abc'; style-src 'self' 'nonce-abc'"
+	if got != want {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestNonces(t *testing.T) {
+	var nonce string
+	page := `<p><script>alert(1)</script></p>`
+	h := Nonces(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce = Nonce(r.Context())
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	}), NonceOptions{})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if len(nonce) != 24 {
+		t.Fatalf("nonce %q", nonce)
+	}
+	if got := rec.Header().Get("Content-Security-Policy"); got != DefaultNoncePolicy(nonce) {
+		t.Errorf("policy %q", got)
+	}
+	// Injected markup is never given the nonce
+	if rec.Body.String() != page {
+		t.Errorf("body rewritten: %s", rec.Body.String())
+	}
+
+	first := nonce
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if nonce == first {
+		t.Error("nonce reused across requests")
+	}
+}
+
+func TestNonceBuilders(t *testing.T) {
+	ctx := WithNonce(context.Background(), "abc")
+
+	for _, s := range []string{
+		Script(ctx, elements.ScriptProps{Src: "/app.js"}),
+		Style(ctx, elements.StyleProps{InnerHTML: "p{}"}),
+		Link(ctx, elements.LinkProps{Href: "/app.css"}),
+	} {
+		if !strings.Contains(s, `nonce="abc"`) {
+			t.Errorf("no nonce: %s", s)
+		}
+	}
+
+	s := Script(ctx, elements.ScriptProps{GlobalProps: elements.GlobalProps{Nonce: "own"}})
+	if !strings.Contains(s, `nonce="own"`) || strings.Contains(s, "abc") {
+		t.Errorf("nonce replaced: %s", s)
+	}
+	if s := Script(context.Background(), elements.ScriptProps{}); strings.Contains(s, "nonce") {
+		t.Errorf("nonce outside Nonces: %s", s)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	h := DefaultHeaders()
+	h.HSTS.Preload = true
+	h.PermissionsPolicy["fullscreen"] = []string{"self", "https://video.example.com"}
+	h.PermissionsPolicy["autoplay"] = []string{"*"}
+
+	var nonce string
+	handler := h.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce = Nonce(r.Context())
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	for name, want := range map[string]string{
+		"Content-Security-Policy":      DefaultNoncePolicy(nonce),
+		"Strict-Transport-Security":    "max-age=63072000; includeSubDomains; preload",
+		"X-Content-Type-Options":       "nosniff",
+		"Referrer-Policy":              "strict-origin-when-cross-origin",
+		"Permissions-Policy":           `autoplay=*, camera=(), fullscreen=(self "https://video.example.com"), geolocation=(), microphone=()`,
+		"Cross-Origin-Opener-Policy":   "same-origin",
+		"Cross-Origin-Resource-Policy": "same-origin",
+		"Cross-Origin-Embedder-Policy": "",
+	} {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+	if nonce == "" {
+		t.Error("no nonce in context")
+	}
+}
+
+func TestHeadersApply(t *testing.T) {
+	h := Headers{
+		CSP:           &CSP{DefaultSrc: []Source{Self}},
+		CSPReportOnly: true,
+		HSTS:          &HSTS{MaxAge: time.Hour},
+	}
+	header := http.Header{}
+	h.Apply(header)
+
+	if got := header.Get("Content-Security-Policy-Report-Only"); got != "default-src 'self'" {
+		t.Errorf("csp %q", got)
+	}
+	if header.Get("Content-Security-Policy") != "" {
+		t.Error("enforced a report-only policy")
+	}
+	if got := header.Get("Strict-Transport-Security"); got != "max-age=3600" {
+		t.Errorf("hsts %q", got)
+	}
+	if len(header) != 2 {
+		t.Errorf("unset fields sent: %v", header)
+	}
+
+	// A nonce-based policy needs the request, so Apply leaves it to Handler
+	h.CSPNonce = true
+	header = http.Header{}
+	h.Apply(header)
+	if header.Get("Content-Security-Policy-Report-Only") != "" {
+		t.Error("applied a nonce policy without a nonce")
+	}
+}
+
+var csrfKeyBytes = bytes.Repeat([]byte("k"), 32)
+
+func TestCSRFSignVerify(t *testing.T) {
+	secret := bytes.Repeat([]byte("s"), 32)
+	a, err := signCSRF(csrfKeyBytes, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := signCSRF(csrfKeyBytes, secret)
+
+	// Tokens rotate with every response, and earlier ones stay valid
+	if a == b || !verifyCSRF(csrfKeyBytes, secret, a) || !verifyCSRF(csrfKeyBytes, secret, b) {
+		t.Fatalf("rotation: %q %q", a, b)
+	}
+
+	tampered := []byte(a)
+	tampered[len(tampered)-1] ^= 1
+	for name, ok := range map[string]bool{
+		"other key":    verifyCSRF(bytes.Repeat([]byte("x"), 32), secret, a),
+		"other secret": verifyCSRF(csrfKeyBytes, bytes.Repeat([]byte("x"), 32), a),
+		"tampered":     verifyCSRF(csrfKeyBytes, secret, string(tampered)),
+		"empty":        verifyCSRF(csrfKeyBytes, secret, ""),
+	} {
+		if ok {
+			t.Errorf("%s verified", name)
+		}
+	}
+}
+
+func TestCSRFShortKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("accepted a 16 byte key")
+		}
+	}()
+	CSRF(http.NotFoundHandler(), CSRFOptions{Key: make([]byte, 16)})
+}
+
+// csrfHandler builds its page with the request context
+func csrfHandler(page func(ctx context.Context) string) http.Handler {
+	return CSRF(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page(r.Context())))
+	}), CSRFOptions{Key: csrfKeyBytes, TrustedOrigins: []string{"https://app.example.com"}})
+}
+
+func TestCSRF(t *testing.T) {
+	post := elements.FormOptions.Method.Post
+	h := csrfHandler(func(ctx context.Context) string {
+		return Body(ctx, elements.BodyProps{InnerHTML: Form(ctx, elements.FormProps{Method: post, Action: "/save"}) +
+			Form(ctx, elements.FormProps{Method: post, Action: "https://app.example.com/save"}) +
+			Form(ctx, elements.FormProps{Method: post, Action: "https://evil.com/steal"}) +
+			Form(ctx, elements.FormProps{Method: post, Action: "//evil.com/"}) +
+			Form(ctx, elements.FormProps{Action: "/search"})})
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "https://example.com/", nil))
+	page := strings.Join(strings.Fields(rec.Body.String()), " ")
+
+	if strings.Count(page, `name="csrf_token"`) != 2 || !strings.Contains(page, `action="/save" method="post" ><input`) {
+		t.Fatalf("token not only in the same-origin post forms: %s", page)
+	}
+	if !strings.Contains(page, `hx-headers="{&#34;X-CSRF-Token&#34;:&#34;`) {
+		t.Errorf("no hx-headers: %s", page)
+	}
+	if rec.Header().Get("Vary") != "Cookie" {
+		t.Errorf("Vary %q", rec.Header().Get("Vary"))
+	}
+
+	cookie := rec.Result().Cookies()[0]
+	i := strings.Index(page, `value="`) + len(`value="`)
+	token := page[i : i+strings.Index(page[i:], `"`)]
+
+	for _, c := range []struct {
+		name   string
+		header http.Header
+		form   url.Values
+		err    error
+	}{
+		{"form token", nil, url.Values{"csrf_token": {token}}, nil},
+		{"header token", http.Header{"X-Csrf-Token": {token}}, nil, nil},
+		{"missing token", nil, nil, ErrCSRFToken},
+		{"foreign origin", http.Header{"Origin": {"https://evil.com"}, "X-Csrf-Token": {token}}, nil, ErrCSRFOrigin},
+		{"foreign referer", http.Header{"Referer": {"https://evil.com/page"}, "X-Csrf-Token": {token}}, nil, ErrCSRFOrigin},
+		{"same referer", http.Header{"Referer": {"https://example.com/page"}, "X-Csrf-Token": {token}}, nil, nil},
+		{"trusted origin", http.Header{"Origin": {"https://app.example.com"}, "X-Csrf-Token": {token}}, nil, nil},
+	} {
+		req := httptest.NewRequest("POST", "https://example.com/save", strings.NewReader(c.form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for k, v := range c.header {
+			req.Header[k] = v
+		}
+		req.AddCookie(cookie)
+
+		var got error
+		CSRF(http.NotFoundHandler(), CSRFOptions{
+			Key:            csrfKeyBytes,
+			TrustedOrigins: []string{"https://app.example.com"},
+			ErrorHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = CSRFError(r)
+			}),
+		}).ServeHTTP(httptest.NewRecorder(), req)
+
+		if !errors.Is(got, c.err) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.err)
+		}
+	}
+}
+
+func TestCSRFVaryOnlyWithToken(t *testing.T) {
+	h := csrfHandler(func(ctx context.Context) string {
+		return `<p>no forms</p>` + Form(ctx, elements.FormProps{Method: elements.FormOptions.Method.Post, Action: "https://evil.com/"})
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "https://example.com/", nil))
+	if rec.Header().Get("Vary") != "" || strings.Contains(rec.Body.String(), "csrf_token") {
+		t.Errorf("Vary %q: %s", rec.Header().Get("Vary"), rec.Body.String())
+	}
+}
+
+func TestCSRFBodyHeaders(t *testing.T) {
+	ctx := context.WithValue(context.Background(), csrfKey{}, csrfToken{token: "tok", header: DefaultCSRFHeader, vary: func() {}})
+
+	got := Body(ctx, elements.BodyProps{GlobalProps: elements.GlobalProps{
+		Htmx: map[string]string{"headers": `{&#34;X-Tenant&#34;:&#34;acme&#34;}`, "boost": "true"},
+	}})
+	if !strings.Contains(got, `hx-headers="{&#34;X-CSRF-Token&#34;:&#34;tok&#34;,&#34;X-Tenant&#34;:&#34;acme&#34;}"`) || !strings.Contains(got, `hx-boost="true"`) {
+		t.Errorf("headers not merged: %s", got)
+	}
+
+	// A js: expression cannot be merged and is left alone
+	got = Body(ctx, elements.BodyProps{GlobalProps: elements.GlobalProps{Htmx: map[string]string{"headers": "js:{a: 1}"}}})
+	if !strings.Contains(got, `hx-headers="js:{a: 1}"`) {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestCSRFPassesWriterThrough(t *testing.T) {
+	flushes := false
+	CSRF(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, flushes = w.(http.Flusher)
+	}), CSRFOptions{Key: csrfKeyBytes}).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !flushes {
+		t.Error("response writer hides http.Flusher")
+	}
+}
@@ -0,0 +1,427 @@
+/*
+ * Package seo renders the <head> tags search engines and social networks
+ * read: title, description, canonical URL, robots directives, OpenGraph,
+ * Twitter cards, theme colors and the viewport.
+ */
+package seo
+
+import (
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitpartio/Mx/elements"
+)
+
+func init() {
+	TwitterOptions = twitterOptions{
+		Card: twitterCardOptions{
+			Summary:           twitterCardOptionSummary,
+			SummaryLargeImage: twitterCardOptionSummaryLargeImage,
+			App:               twitterCardOptionApp,
+			Player:            twitterCardOptionPlayer,
+		},
+	}
+}
+
+// Responsive layout at device width, used when SEO.Viewport is empty
+const DefaultViewport = "width=device-width, initial-scale=1"
+
+/*
+ * SEO describes a page. Empty fields render nothing, and OpenGraph and
+ * Twitter values left empty fall back to the page's own title,
+ * description and canonical URL.
+ */
+type SEO struct {
+	Title       string
+	Description string
+	Canonical   string
+	// Translations of the page, hreflang to URL, e.g. "de": "https://example.com/de/"
+	Alternates map[string]string
+	Robots     Robots
+	OpenGraph  *OpenGraph
+	Twitter    *Twitter
+	ThemeColor []ThemeColor
+	Viewport   string
+}
+
+/*
+ * Robots directives for <meta name="robots">. The zero value allows
+ * everything, which is the default, and renders nothing.
+ * Ref: https://developers.google.com/search/docs/crawling-indexing/robots-meta-tag
+ */
+type Robots struct {
+	NoIndex      bool
+	NoFollow     bool
+	NoArchive    bool
+	NoSnippet    bool
+	NoImageIndex bool
+	NoTranslate  bool
+	// Characters, 0 for none
+	MaxSnippet *int
+	// "none", "standard" or "large"
+	MaxImagePreview string
+	// Seconds, 0 for a static image only
+	MaxVideoPreview  *int
+	UnavailableAfter time.Time
+}
+
+func (r Robots) String() string {
+	var d []string
+	if r.NoIndex {
+		d = append(d, "noindex")
+	}
+	if r.NoFollow {
+		d = append(d, "nofollow")
+	}
+	if r.NoArchive {
+		d = append(d, "noarchive")
+	}
+	if r.NoSnippet {
+		d = append(d, "nosnippet")
+	}
+	if r.NoImageIndex {
+		d = append(d, "noimageindex")
+	}
+	if r.NoTranslate {
+		d = append(d, "notranslate")
+	}
+	if r.MaxSnippet != nil {
+		d = append(d, "max-snippet:"+strconv.Itoa(*r.MaxSnippet))
+	}
+	if r.MaxImagePreview != "" {
+		d = append(d, "max-image-preview:"+r.MaxImagePreview)
+	}
+	if r.MaxVideoPreview != nil {
+		d = append(d, "max-video-preview:"+strconv.Itoa(*r.MaxVideoPreview))
+	}
+	if !r.UnavailableAfter.IsZero() {
+		d = append(d, "unavailable_after:"+r.UnavailableAfter.UTC().Format(time.RFC3339))
+	}
+	return strings.Join(d, ", ")
+}
+
+/*
+ * OpenGraph properties. Ref: https://ogp.me
+ */
+type OpenGraph struct {
+	// "website" when empty
+	Type             string
+	Title            string
+	Description      string
+	URL              string
+	SiteName         string
+	Locale           string
+	LocaleAlternates []string
+	Images           []Image
+	Article          *Article
+}
+
+type Image struct {
+	URL    string
+	Type   string
+	Width  int
+	Height int
+	Alt    string
+}
+
+// Article properties, for OpenGraph.Type "article"
+type Article struct {
+	PublishedTime time.Time
+	ModifiedTime  time.Time
+	Authors       []string
+	Section       string
+	Tags          []string
+}
+
+/*
+ * Twitter card properties. X reads og: properties for anything missing,
+ * so values identical to the OpenGraph ones are not repeated.
+ * Ref: https://developer.x.com/en/docs/twitter-for-websites/cards/overview/markup
+ */
+type Twitter struct {
+	// Summary, or SummaryLargeImage when the page has an image, when nil
+	Card        func() twitterCardOption
+	Site        string
+	Creator     string
+	Title       string
+	Description string
+	Image       string
+	ImageAlt    string
+}
+
+type ThemeColor struct {
+	Color string
+	// Media query, e.g. "(prefers-color-scheme: dark)"
+	Media string
+}
+
+/*
+ * Head renders the page's tags. Each name and property appears once;
+ * a tag that would repeat an earlier one is dropped.
+ */
+func (s SEO) Head() string {
+	h := &head{seen: map[string]bool{}}
+
+	h.add("charset", elements.Meta(elements.MetaProps{Charset: "utf-8"}))
+
+	viewport := s.Viewport
+	if viewport == "" {
+		viewport = DefaultViewport
+	}
+	h.name("viewport", viewport, "")
+
+	if s.Title != "" {
+		h.add("title", elements.Title(elements.TitleProps{InnerHTML: html.EscapeString(s.Title)}))
+	}
+	h.name("description", s.Description, "")
+	if robots := s.Robots.String(); robots != "" {
+		h.name("robots", robots, "")
+	}
+	if s.Canonical != "" {
+		h.add("canonical", elements.Canonical(html.EscapeString(s.Canonical)))
+	}
+
+	hreflangs := make([]string, 0, len(s.Alternates))
+	for hreflang := range s.Alternates {
+		hreflangs = append(hreflangs, hreflang)
+	}
+	sort.Strings(hreflangs)
+	for _, hreflang := range hreflangs {
+		h.add("alternate "+hreflang, elements.Link(elements.LinkProps{
+			Rel:      elements.LinkRelOptions{elements.LinkOptions.Rel.Alternate},
+			Hreflang: html.EscapeString(hreflang),
+			Href:     html.EscapeString(s.Alternates[hreflang]),
+		}))
+	}
+
+	for _, c := range s.ThemeColor {
+		h.name("theme-color", c.Color, c.Media)
+	}
+
+	og := s.openGraph()
+	if og != nil {
+		h.openGraph(*og)
+	}
+	if tw := s.twitter(og); tw != nil {
+		h.twitter(*tw, og)
+	}
+
+	return h.String()
+}
+
+// openGraph fills the OpenGraph properties the page already knows
+func (s SEO) openGraph() *OpenGraph {
+	if s.OpenGraph == nil {
+		return nil
+	}
+
+	og := *s.OpenGraph
+	if og.Type == "" {
+		og.Type = "website"
+	}
+	if og.Title == "" {
+		og.Title = s.Title
+	}
+	if og.Description == "" {
+		og.Description = s.Description
+	}
+	if og.URL == "" {
+		og.URL = s.Canonical
+	}
+	return &og
+}
+
+// twitter fills the card from the page when there are no og: properties
+// for X to read instead
+func (s SEO) twitter(og *OpenGraph) *Twitter {
+	if s.Twitter == nil {
+		return nil
+	}
+
+	t := *s.Twitter
+	if og == nil {
+		if t.Title == "" {
+			t.Title = s.Title
+		}
+		if t.Description == "" {
+			t.Description = s.Description
+		}
+	}
+	return &t
+}
+
+type head struct {
+	seen map[string]bool
+	tags []string
+}
+
+func (h *head) add(key, tag string) {
+	if h.seen[key] {
+		return
+	}
+	h.seen[key] = true
+	h.tags = append(h.tags, tag)
+}
+
+func (h *head) name(name, content, media string) {
+	if content == "" {
+		return
+	}
+	h.add("name "+name+" "+media, elements.Meta(elements.MetaProps{
+		Name:    name,
+		Content: html.EscapeString(content),
+		Media:   html.EscapeString(media),
+	}))
+}
+
+func (h *head) property(property, content string) {
+	if content == "" {
+		return
+	}
+	h.add("property "+property+" "+content, elements.Meta(elements.MetaProps{
+		Property: property,
+		Content:  html.EscapeString(content),
+	}))
+}
+
+// once drops a property repeated with a different value, for properties
+// that are not arrays
+func (h *head) once(property, content string) {
+	if content == "" || h.seen["property "+property] {
+		return
+	}
+	h.seen["property "+property] = true
+	h.property(property, content)
+}
+
+func (h *head) openGraph(og OpenGraph) {
+	h.once("og:type", og.Type)
+	h.once("og:title", og.Title)
+	h.once("og:description", og.Description)
+	h.once("og:url", og.URL)
+	h.once("og:site_name", og.SiteName)
+	h.once("og:locale", og.Locale)
+	for _, locale := range og.LocaleAlternates {
+		h.property("og:locale:alternate", locale)
+	}
+
+	// Structured properties belong to the og:image before them
+	for _, img := range og.Images {
+		if img.URL == "" || h.seen["property og:image "+img.URL] {
+			continue
+		}
+		h.property("og:image", img.URL)
+		h.tags = append(h.tags, structured("og:image:type", img.Type))
+		h.tags = append(h.tags, structured("og:image:width", dimension(img.Width)))
+		h.tags = append(h.tags, structured("og:image:height", dimension(img.Height)))
+		h.tags = append(h.tags, structured("og:image:alt", img.Alt))
+	}
+
+	if a := og.Article; a != nil {
+		if !a.PublishedTime.IsZero() {
+			h.once("article:published_time", a.PublishedTime.Format(time.RFC3339))
+		}
+		if !a.ModifiedTime.IsZero() {
+			h.once("article:modified_time", a.ModifiedTime.Format(time.RFC3339))
+		}
+		for _, author := range a.Authors {
+			h.property("article:author", author)
+		}
+		h.once("article:section", a.Section)
+		for _, tag := range a.Tags {
+			h.property("article:tag", tag)
+		}
+	}
+}
+
+func (h *head) twitter(t Twitter, og *OpenGraph) {
+	var ogImage, ogImageAlt string
+	var fallback OpenGraph
+	if og != nil {
+		fallback = *og
+		if len(og.Images) > 0 {
+			ogImage, ogImageAlt = og.Images[0].URL, og.Images[0].Alt
+		}
+	}
+
+	card := "summary"
+	if t.Card != nil {
+		card = t.Card().String()
+	} else if t.Image != "" || ogImage != "" {
+		card = "summary_large_image"
+	}
+
+	h.name("twitter:card", card, "")
+	h.name("twitter:site", t.Site, "")
+	h.name("twitter:creator", t.Creator, "")
+	if t.Title != fallback.Title {
+		h.name("twitter:title", t.Title, "")
+	}
+	if t.Description != fallback.Description {
+		h.name("twitter:description", t.Description, "")
+	}
+	if t.Image != ogImage {
+		h.name("twitter:image", t.Image, "")
+	}
+	if t.ImageAlt != ogImageAlt {
+		h.name("twitter:image:alt", t.ImageAlt, "")
+	}
+}
+
+func (h *head) String() string {
+	return strings.Join(h.tags, "")
+}
+
+func structured(property, content string) string {
+	if content == "" {
+		return ""
+	}
+	return elements.Meta(elements.MetaProps{Property: property, Content: html.EscapeString(content)})
+}
+
+func dimension(px int) string {
+	if px <= 0 {
+		return ""
+	}
+	return strconv.Itoa(px)
+}
+
+/*
+ * Options
+ */
+type twitterOptions struct {
+	Card twitterCardOptions
+}
+
+var TwitterOptions twitterOptions
+
+/* Card */
+type twitterCardOption struct{ string }
+
+func (o twitterCardOption) String() string { return o.string }
+
+func twitterCardOptionSummary() twitterCardOption {
+	return twitterCardOption{"summary"}
+}
+
+func twitterCardOptionSummaryLargeImage() twitterCardOption {
+	return twitterCardOption{"summary_large_image"}
+}
+
+func twitterCardOptionApp() twitterCardOption {
+	return twitterCardOption{"app"}
+}
+
+func twitterCardOptionPlayer() twitterCardOption {
+	return twitterCardOption{"player"}
+}
+
+type twitterCardOptions struct {
+	Summary           func() twitterCardOption
+	SummaryLargeImage func() twitterCardOption
+	App               func() twitterCardOption
+	Player            func() twitterCardOption
+}
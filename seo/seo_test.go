@@ -0,0 +1,96 @@
+package seo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// render renders s with the builders' padding collapsed to single spaces
+func render(s SEO) string {
+	return strings.Join(strings.Fields(s.Head()), " ")
+}
+
+func TestRobots(t *testing.T) {
+	zero := 0
+	r := Robots{
+		NoIndex:          true,
+		MaxSnippet:       &zero,
+		MaxImagePreview:  "large",
+		UnavailableAfter: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	want := "noindex, max-snippet:0, max-image-preview:large, unavailable_after:2030-01-02T03:04:05Z"
+	if got := r.String(); got != want {
+		t.Errorf("got %q", got)
+	}
+	if (Robots{}).String() != "" {
+		t.Error("zero value renders directives")
+	}
+}
+
+func TestHead(t *testing.T) {
+	s := SEO{
+		Title:       `Tom & "Jerry"`,
+		Description: "A cat and a mouse",
+		Canonical:   "https://example.com/tom?a=1&b=2",
+		Alternates:  map[string]string{"de": "https://example.com/de/", "en": "https://example.com/"},
+		ThemeColor:  []ThemeColor{{Color: "#fff"}, {Color: "#000", Media: "(prefers-color-scheme: dark)"}},
+		OpenGraph: &OpenGraph{
+			Images: []Image{{URL: "https://example.com/a.png", Width: 1200}, {URL: "https://example.com/a.png"}},
+		},
+		Twitter: &Twitter{Site: "@example", Title: `Tom & "Jerry"`},
+	}
+	got := render(s)
+
+	for _, want := range []string{
+		`<meta charset="utf-8" />`,
+		`content="width=device-width, initial-scale=1"`,
+		`>Tom &amp; &#34;Jerry&#34;</title>`,
+		`href="https://example.com/tom?a=1&amp;b=2"`,
+		`hreflang="de"`,
+		`media="(prefers-color-scheme: dark)"`,
+		`content="website" property="og:type"`,
+		`content="Tom &amp; &#34;Jerry&#34;" property="og:title"`,
+		`content="https://example.com/tom?a=1&amp;b=2" property="og:url"`,
+		`content="1200" property="og:image:width"`,
+		`content="summary_large_image" name="twitter:card"`,
+		`content="@example" name="twitter:site"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	if strings.Index(got, `hreflang="de"`) > strings.Index(got, `hreflang="en"`) {
+		t.Error("alternates not sorted")
+	}
+	if strings.Count(got, `property="og:image"`) != 1 {
+		t.Error("repeated og:image")
+	}
+	// X reads og: properties, so values equal to them are not repeated
+	for _, dup := range []string{"twitter:title", "twitter:description", "twitter:image"} {
+		if strings.Contains(got, dup) {
+			t.Errorf("repeated %s", dup)
+		}
+	}
+}
+
+func TestTwitterWithoutOpenGraph(t *testing.T) {
+	got := render(SEO{
+		Title:       "Home",
+		Description: "Welcome",
+		Twitter:     &Twitter{Card: TwitterOptions.Card.Summary},
+	})
+
+	for _, want := range []string{
+		`content="summary" name="twitter:card"`,
+		`content="Home" name="twitter:title"`,
+		`content="Welcome" name="twitter:description"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	if strings.Contains(got, "og:") {
+		t.Error("rendered OpenGraph without one")
+	}
+}
@@ -0,0 +1,48 @@
+package sse
+
+// Ref: https://htmx.org/extensions/sse/
+
+import (
+	"html"
+	"strings"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * Connect adds the attributes opening an event stream on the element,
+ * hx-ext="sse" and sse-connect, to props. Descendants listen with Swap or
+ * Trigger. The extension attributes carry a data- prefix, which htmx
+ * reads too.
+ */
+func Connect(props elements.GlobalProps, url string) elements.GlobalProps {
+	props.Htmx = utils.WithExtension(props.Htmx, "sse")
+	props.Data = utils.With(props.Data, "sse-connect", html.EscapeString(url))
+	return props
+}
+
+/*
+ * Swap adds sse-swap to props, replacing the element's content with the
+ * data of the named events. hx-swap chooses how, e.g. "beforeend" to
+ * append.
+ */
+func Swap(props elements.GlobalProps, names ...string) elements.GlobalProps {
+	props.Data = utils.With(props.Data, "sse-swap", html.EscapeString(strings.Join(names, ",")))
+	return props
+}
+
+/*
+ * Close adds sse-close to props, closing the connection when the named
+ * event arrives
+ */
+func Close(props elements.GlobalProps, name string) elements.GlobalProps {
+	props.Data = utils.With(props.Data, "sse-close", html.EscapeString(name))
+	return props
+}
+
+// Trigger is the hx-trigger value firing the element's request on the
+// named event
+func Trigger(name string) string {
+	return "sse:" + name
+}
@@ -0,0 +1,58 @@
+package sse
+
+// Ref: https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ * A message of the event stream
+ */
+type Event struct {
+	// Assigned by Publish, sent back by reconnecting browsers as
+	// Last-Event-ID
+	ID string
+	// Event type, matched by sse-swap and hx-trigger="sse:<name>";
+	// "message" when empty
+	Name string
+	// Payload, usually an HTML fragment. Newlines are kept.
+	Data string
+	// How long browsers wait before reconnecting, unchanged when zero
+	Retry time.Duration
+
+	seq uint64
+	at  time.Time
+}
+
+// WriteTo writes the event in the text/event-stream format
+func (e Event) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + oneLine(e.ID) + "\n")
+	}
+	if e.Name != "" {
+		b.WriteString("event: " + oneLine(e.Name) + "\n")
+	}
+	if e.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	data := strings.ReplaceAll(e.Data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// oneLine keeps field values from starting new fields
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,365 @@
+/*
+ * Package sse pushes events to browsers over Server-Sent Events, for the
+ * htmx sse extension. A Broker fans events out to the subscribers of
+ * topics and replays those missed by reconnecting browsers.
+ *
+ *	feed := sse.New(sse.Options{})
+ *	http.Handle("/events", feed) // GET /events?topic=orders
+ *
+ *	// On the page
+ *	Div(DivProps{GlobalProps: sse.Connect(GlobalProps{}, "/events?topic=orders"),
+ *		InnerHTML: Ul(UlProps{GlobalProps: sse.Swap(GlobalProps{}, "order")})})
+ *
+ *	// Anywhere else
+ *	feed.Send("orders", "order", Li(LiProps{InnerHTML: "New order"}))
+ */
+package sse
+
+import (
+	"container/list"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBuffer     = 16
+	DefaultHistory    = 100
+	DefaultHistoryAge = 10 * time.Minute
+	DefaultMaxTopics  = 1024
+	DefaultHeartbeat  = 15 * time.Second
+)
+
+/*
+ * Options
+ */
+type Options struct {
+	// Events queued per client; a client falling further behind is
+	// disconnected and catches up by replay when it reconnects.
+	// DefaultBuffer when zero.
+	Buffer int
+	// Events kept per topic for replay, DefaultHistory when zero and none
+	// when negative
+	History int
+	// How long events stay replayable, DefaultHistoryAge when zero. Topics
+	// with nothing newer are forgotten.
+	HistoryAge time.Duration
+	// Topics whose history is kept, DefaultMaxTopics when zero; the one
+	// published to least recently is forgotten first
+	MaxTopics int
+	// Interval of the comments keeping idle connections open,
+	// DefaultHeartbeat when zero
+	Heartbeat time.Duration
+	// Reconnection delay sent to browsers, theirs when zero
+	Retry time.Duration
+	// Topics a request subscribes to, the "topic" query parameters when nil.
+	// Check authorization here.
+	Topics func(r *http.Request) []string
+}
+
+/*
+ * Broker distributes events to subscribers by topic. Create one with New.
+ */
+type Broker struct {
+	opts Options
+
+	mu      sync.Mutex
+	seq     uint64
+	clients map[string]map[*client]struct{}
+	history map[string]*history
+	// Topics with history, most recently published first
+	recent *list.List
+	closed bool
+
+	now func() time.Time
+}
+
+// The replayable events of a topic
+type history struct {
+	topic  string
+	events []Event
+	el     *list.Element
+}
+
+type client struct {
+	ch     chan Event
+	topics []string
+	closed bool
+}
+
+/*
+ * A live subscription; events arrive on C, which is closed once the
+ * subscriber is dropped for falling behind, unsubscribes or the broker
+ * closes
+ */
+type Subscription struct {
+	C <-chan Event
+
+	b *Broker
+	c *client
+}
+
+// New
+func New(opts Options) *Broker {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBuffer
+	}
+	if opts.History == 0 {
+		opts.History = DefaultHistory
+	}
+	if opts.HistoryAge <= 0 {
+		opts.HistoryAge = DefaultHistoryAge
+	}
+	if opts.MaxTopics <= 0 {
+		opts.MaxTopics = DefaultMaxTopics
+	}
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = DefaultHeartbeat
+	}
+
+	return &Broker{
+		opts: opts,
+		// IDs start from the clock so they keep increasing across restarts
+		seq:     uint64(time.Now().UnixMicro()),
+		clients: map[string]map[*client]struct{}{},
+		history: map[string]*history{},
+		recent:  list.New(),
+		now:     time.Now,
+	}
+}
+
+/*
+ * Publish sends e to the subscribers of topic, assigning its ID, and
+ * returns it as sent
+ */
+func (b *Broker) Publish(topic string, e Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	e.seq = b.seq
+	e.ID = strconv.FormatUint(e.seq, 10)
+	e.at = b.now()
+
+	if b.opts.History > 0 {
+		b.remember(topic, e)
+	}
+
+	for c := range b.clients[topic] {
+		select {
+		case c.ch <- e:
+		default:
+			b.drop(c)
+		}
+	}
+	return e
+}
+
+// remember adds e to the history of topic and forgets idle topics, with
+// b.mu held
+func (b *Broker) remember(topic string, e Event) {
+	h, ok := b.history[topic]
+	if !ok {
+		h = &history{topic: topic, el: b.recent.PushFront(topic)}
+		b.history[topic] = h
+	}
+	b.recent.MoveToFront(h.el)
+
+	h.events = append(h.events, e)
+	if len(h.events) > b.opts.History {
+		h.events = append([]Event(nil), h.events[len(h.events)-b.opts.History:]...)
+	}
+
+	// Topics at the back were published to longest ago, so the idle ones
+	// are all found there
+	cutoff := e.at.Add(-b.opts.HistoryAge)
+	for el := b.recent.Back(); el != nil; el = b.recent.Back() {
+		old := b.history[el.Value.(string)]
+		if b.recent.Len() <= b.opts.MaxTopics && old.events[len(old.events)-1].at.After(cutoff) {
+			break
+		}
+		b.recent.Remove(el)
+		delete(b.history, old.topic)
+	}
+}
+
+/*
+ * Send publishes an HTML fragment as the named event, swapped by the
+ * elements listening with Swap
+ */
+func (b *Broker) Send(topic, name, html string) {
+	b.Publish(topic, Event{Name: name, Data: html})
+}
+
+/*
+ * Subscribe listens to topics. With lastID, the ID of the last event
+ * received, later events still in the history are queued first.
+ */
+func (b *Broker) Subscribe(lastID string, topics ...string) *Subscription {
+	topics = unique(topics)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var missed []Event
+	if last, err := strconv.ParseUint(lastID, 10, 64); err == nil {
+		cutoff := b.now().Add(-b.opts.HistoryAge)
+		for _, topic := range topics {
+			h, ok := b.history[topic]
+			if !ok {
+				continue
+			}
+			for _, e := range h.events {
+				if e.seq > last && e.at.After(cutoff) {
+					missed = append(missed, e)
+				}
+			}
+		}
+		sort.Slice(missed, func(i, j int) bool { return missed[i].seq < missed[j].seq })
+	}
+
+	size := b.opts.Buffer
+	if len(missed) > size {
+		size = len(missed)
+	}
+	c := &client{ch: make(chan Event, size), topics: topics}
+	for _, e := range missed {
+		c.ch <- e
+	}
+
+	if b.closed {
+		c.closed = true
+		close(c.ch)
+	} else {
+		for _, topic := range topics {
+			if b.clients[topic] == nil {
+				b.clients[topic] = map[*client]struct{}{}
+			}
+			b.clients[topic][c] = struct{}{}
+		}
+	}
+
+	return &Subscription{C: c.ch, b: b, c: c}
+}
+
+func unique(topics []string) []string {
+	seen := make(map[string]bool, len(topics))
+	out := make([]string, 0, len(topics))
+	for _, t := range topics {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Unsubscribe stops the subscription and closes C
+func (s *Subscription) Unsubscribe() {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	s.b.drop(s.c)
+}
+
+// drop removes c, with b.mu held
+func (b *Broker) drop(c *client) {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for _, topic := range c.topics {
+		delete(b.clients[topic], c)
+		if len(b.clients[topic]) == 0 {
+			delete(b.clients, topic)
+		}
+	}
+	close(c.ch)
+}
+
+// Subscribers counts the subscriptions to topic
+func (b *Broker) Subscribers(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.clients[topic])
+}
+
+// Close disconnects every subscriber; later subscriptions end at once
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for _, clients := range b.clients {
+		for c := range clients {
+			b.drop(c)
+		}
+	}
+}
+
+/*
+ * ServeHTTP streams the request's topics as text/event-stream, starting
+ * with the events after its Last-Event-ID
+ */
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	var topics []string
+	if b.opts.Topics != nil {
+		topics = b.opts.Topics(r)
+	} else {
+		topics = r.URL.Query()["topic"]
+	}
+	if len(topics) == 0 {
+		http.Error(w, "no topic", http.StatusBadRequest)
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		// For clients that cannot set headers, such as polyfills
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+	sub := b.Subscribe(lastID, topics...)
+	defer sub.Unsubscribe()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	// Disables response buffering in nginx
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if b.opts.Retry > 0 {
+		w.Write([]byte("retry: " + strconv.FormatInt(b.opts.Retry.Milliseconds(), 10) + "\n\n"))
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(b.opts.Heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if _, err := e.WriteTo(w); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,94 @@
+package sse
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplay(t *testing.T) {
+	b := New(Options{})
+	first := b.Publish("orders", Event{Name: "order", Data: "1"})
+	b.Publish("stock", Event{Name: "stock", Data: "ignored"})
+	b.Publish("orders", Event{Name: "order", Data: "2"})
+
+	sub := b.Subscribe(first.ID, "orders")
+	defer sub.Unsubscribe()
+	b.Send("orders", "order", "3")
+
+	for _, want := range []string{"2", "3"} {
+		select {
+		case e := <-sub.C:
+			if e.Data != want {
+				t.Fatalf("got %q, want %q", e.Data, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("no event")
+		}
+	}
+}
+
+func TestSlowClient(t *testing.T) {
+	b := New(Options{Buffer: 1})
+	sub := b.Subscribe("", "orders")
+	b.Send("orders", "order", "1")
+	b.Send("orders", "order", "2")
+
+	<-sub.C
+	if _, ok := <-sub.C; ok || b.Subscribers("orders") != 0 {
+		t.Fatal("slow client was not dropped")
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	b := New(Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest("GET", "/events?topic=orders", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		b.ServeHTTP(w, r)
+		close(done)
+	}()
+	for b.Subscribers("orders") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	b.Send("orders", "order", "<li>a</li>\n<li>b</li>")
+	b.Close()
+	<-done
+	cancel()
+
+	body := w.Body.String()
+	if w.Header().Get("Content-Type") != "text/event-stream" ||
+		!strings.Contains(body, "event: order\ndata: <li>a</li>\ndata: <li>b</li>\n\n") {
+		t.Fatalf("unexpected stream %q", body)
+	}
+}
+
+func TestHistoryPruned(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := New(Options{MaxTopics: 2, HistoryAge: time.Minute})
+	b.now = func() time.Time { return now }
+
+	first := b.Publish("a", Event{Name: "x", Data: "a"})
+	b.Send("b", "x", "b")
+	b.Send("c", "x", "c")
+	if len(b.history) != 2 || b.history["a"] != nil {
+		t.Fatalf("least recent topic kept: %v", b.history)
+	}
+
+	now = now.Add(2 * time.Minute)
+	b.Send("d", "x", "d")
+	if len(b.history) != 1 || b.history["d"] == nil {
+		t.Fatalf("idle topics kept: %v", b.history)
+	}
+
+	sub := b.Subscribe(first.ID, "a", "b", "c", "d")
+	defer sub.Unsubscribe()
+	if e := <-sub.C; e.Data != "d" || len(sub.C) != 0 {
+		t.Fatalf("replayed %q and %d more", e.Data, len(sub.C))
+	}
+}
@@ -0,0 +1,54 @@
+package ssg
+
+import (
+	"fmt"
+	"os"
+)
+
+// Environment read by Main, set by `mx build`
+const (
+	EnvOut     = "MX_BUILD_OUT"
+	EnvStatic  = "MX_BUILD_STATIC"
+	EnvBaseURL = "MX_BUILD_BASE_URL"
+)
+
+/*
+ * Main builds the site and exits when the program is run by `mx build`.
+ * Otherwise it returns immediately so the program can go on to serve the
+ * site as usual:
+ *
+ *   func main() {
+ *     site := ssg.New()
+ *     site.Page("/", home)
+ *     site.Main()
+ *
+ *     log.Fatal(http.ListenAndServe(":8000", site.Handler()))
+ *   }
+ */
+func (s *Site) Main() {
+	out := os.Getenv(EnvOut)
+	if out == "" {
+		return
+	}
+
+	report, err := s.Build(Options{
+		Out:     out,
+		Static:  os.Getenv(EnvStatic),
+		BaseURL: os.Getenv(EnvBaseURL),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for _, p := range report.Pages {
+		fmt.Fprintln(os.Stderr, "  built", p)
+	}
+	fmt.Fprintf(os.Stderr, "%d page(s), %d static asset(s) written to %s\n", len(report.Pages), report.Assets, out)
+
+	if err := report.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
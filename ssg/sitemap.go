@@ -0,0 +1,42 @@
+package ssg
+
+// Ref: https://www.sitemaps.org/protocol.html
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+type urlset struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// writeSitemap lists the generated index pages under baseURL; Build leaves
+// out pages written as named files (404.html and the like)
+func writeSitemap(out, baseURL string, pages []string) error {
+	base := strings.TrimSuffix(baseURL, "/")
+
+	set := urlset{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	sorted := append([]string(nil), pages...)
+	sort.Strings(sorted)
+	for _, p := range sorted {
+		set.URLs = append(set.URLs, sitemapURL{Loc: base + p})
+	}
+
+	b, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append([]byte(xml.Header), b...)
+
+	return os.WriteFile(filepath.Join(out, "sitemap.xml"), b, 0o644)
+}
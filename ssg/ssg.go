@@ -0,0 +1,364 @@
+/*
+ * Package ssg renders Mx pages ahead of time. Routes are registered with
+ * render functions, parameterized routes are enumerated by generators,
+ * and Build writes minified index.html files, copies static assets and
+ * produces sitemap.xml.
+ */
+package ssg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	. "github.com/bitpartio/Mx/utils"
+)
+
+// Params holds the values of a route's {name} segments
+type Params map[string]string
+
+// RenderFunc renders the page for one set of route params
+type RenderFunc func(params Params) (string, error)
+
+// GeneratorFunc enumerates the params a parameterized route is built for
+type GeneratorFunc func() ([]Params, error)
+
+type route struct {
+	pattern  string
+	generate GeneratorFunc
+	render   RenderFunc
+}
+
+/*
+ * Site is a set of statically rendered routes. Patterns are URL paths
+ * whose segments may be parameters, e.g. "/products/{slug}". A pattern
+ * ending in a file extension ("/404.html") is written as that file,
+ * anything else as a directory containing index.html.
+ */
+type Site struct {
+	routes []route
+}
+
+// New
+func New() *Site {
+	return &Site{}
+}
+
+// Handle registers a route without parameters
+func (s *Site) Handle(pattern string, render RenderFunc) {
+	s.Generate(pattern, nil, render)
+}
+
+// Page registers a route whose page is a fixed string
+func (s *Site) Page(pattern string, page func() string) {
+	s.Handle(pattern, func(Params) (string, error) {
+		return page(), nil
+	})
+}
+
+// Generate registers a parameterized route, rendered once per Params
+// returned by generate
+func (s *Site) Generate(pattern string, generate GeneratorFunc, render RenderFunc) {
+	s.routes = append(s.routes, route{pattern: pattern, generate: generate, render: render})
+}
+
+/*
+ * Options
+ */
+type Options struct {
+	// Output directory, created if missing
+	Out string
+	// Directory copied verbatim into Out
+	Static string
+	// Absolute site URL, enables sitemap.xml
+	BaseURL string
+	// Keep the rendered markup as-is instead of minifying it
+	NoMinify bool
+}
+
+// Page that failed to render or write
+type RenderError struct {
+	Route string
+	Path  string
+	Err   error
+}
+
+func (e RenderError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %v", e.Route, e.Err)
+	}
+	return fmt.Sprintf("%s (%s): %v", e.Route, e.Path, e.Err)
+}
+
+func (e RenderError) Unwrap() error { return e.Err }
+
+// Report describes a build
+type Report struct {
+	Pages  []string
+	Assets int
+	Errors []RenderError
+}
+
+// Err summarizes the render errors, nil when every page was written
+func (r *Report) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	lines := make([]string, len(r.Errors))
+	for i, e := range r.Errors {
+		lines[i] = e.Error()
+	}
+	return fmt.Errorf("ssg: %d page(s) failed:\n  %s", len(r.Errors), strings.Join(lines, "\n  "))
+}
+
+/*
+ * Build renders every route into opts.Out. A failing page does not stop
+ * the build; failures are collected in the report and returned together
+ * by Report.Err. The returned error is reserved for problems with the
+ * output directory, static assets or sitemap.
+ */
+func (s *Site) Build(opts Options) (*Report, error) {
+	if opts.Out == "" {
+		return nil, errors.New("ssg: no output directory")
+	}
+	if err := os.MkdirAll(opts.Out, 0o755); err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	// Index pages, listed in the sitemap
+	var indexes []string
+
+	if opts.Static != "" {
+		n, err := copyDir(opts.Static, opts.Out)
+		if err != nil {
+			return report, fmt.Errorf("ssg: copying static assets: %w", err)
+		}
+		report.Assets = n
+	}
+
+	for _, r := range s.routes {
+		paramsList := []Params{{}}
+		if r.generate != nil {
+			var err error
+			paramsList, err = r.generate()
+			if err != nil {
+				report.Errors = append(report.Errors, RenderError{Route: r.pattern, Err: err})
+				continue
+			}
+		}
+
+		for _, params := range paramsList {
+			p, err := expand(r.pattern, params)
+			if err != nil {
+				report.Errors = append(report.Errors, RenderError{Route: r.pattern, Err: err})
+				continue
+			}
+			if err := s.write(opts, r, p, params); err != nil {
+				report.Errors = append(report.Errors, RenderError{Route: r.pattern, Path: p, Err: err})
+				continue
+			}
+			report.Pages = append(report.Pages, p)
+			if !isFile(r.pattern) {
+				indexes = append(indexes, p)
+			}
+		}
+	}
+
+	if opts.BaseURL != "" {
+		if err := writeSitemap(opts.Out, opts.BaseURL, indexes); err != nil {
+			return report, fmt.Errorf("ssg: writing sitemap: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+func (s *Site) write(opts Options, r route, p string, params Params) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+
+	page, err := r.render(params)
+	if err != nil {
+		return err
+	}
+	if !opts.NoMinify {
+		page = Minify(page)
+	}
+
+	// Servers unescape the request path before looking up the file
+	name, err := url.PathUnescape(outputFile(r.pattern, p))
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(opts.Out, filepath.FromSlash(name))
+	if !within(opts.Out, file) {
+		return fmt.Errorf("%s is outside the output directory", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(page), 0o644)
+}
+
+/*
+ * Handler serves the same routes dynamically, which keeps development and
+ * the generated output in step. Parameter values come from the request
+ * path; generators are not consulted.
+ */
+func (s *Site) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, rt := range s.routes {
+			params, ok := match(rt.pattern, r.URL.EscapedPath())
+			if !ok {
+				continue
+			}
+			page, err := rt.render(params)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, page)
+			return
+		}
+		http.NotFound(w, r)
+	})
+}
+
+// expand substitutes params into a pattern's {name} segments
+func expand(pattern string, params Params) (string, error) {
+	segments := strings.Split(pattern, "/")
+	for i, seg := range segments {
+		name, ok := paramName(seg)
+		if !ok {
+			continue
+		}
+		v, ok := params[name]
+		if !ok || v == "" {
+			return "", fmt.Errorf("missing value for {%s}", name)
+		}
+		if !validParam(v) {
+			return "", fmt.Errorf("invalid value %q for {%s}", v, name)
+		}
+		segments[i] = url.PathEscape(v)
+	}
+	return path.Clean("/" + strings.Join(segments, "/")), nil
+}
+
+// match extracts params from a request path
+func match(pattern, p string) (Params, bool) {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(p, "/"), "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+
+	params := Params{}
+	for i, seg := range want {
+		if name, ok := paramName(seg); ok {
+			v, err := url.PathUnescape(got[i])
+			if err != nil || !validParam(v) {
+				return nil, false
+			}
+			params[name] = v
+			continue
+		}
+		if seg != got[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func paramName(seg string) (string, bool) {
+	if len(seg) > 2 && strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+		return seg[1 : len(seg)-1], true
+	}
+	return "", false
+}
+
+/*
+ * validParam reports whether v can fill a single path segment: not empty,
+ * "." or "..", and free of separators, so it can never climb out of the
+ * output directory
+ */
+func validParam(v string) bool {
+	return v != "" && v != "." && v != ".." && !strings.ContainsAny(v, "/\\\x00")
+}
+
+// isFile reports whether a pattern names a file ("/404.html") rather than
+// a directory; a parameter ending the pattern is always a directory, even
+// when its values contain dots ("/releases/{version}")
+func isFile(pattern string) bool {
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	if _, ok := paramName(last); ok {
+		return false
+	}
+	return path.Ext(last) != ""
+}
+
+// outputFile maps the page path of a pattern to the file written for it
+func outputFile(pattern, p string) string {
+	if isFile(pattern) {
+		return strings.TrimPrefix(p, "/")
+	}
+	return strings.TrimPrefix(path.Join(p, "index.html"), "/")
+}
+
+// within reports whether file lies inside the directory root
+func within(root, file string) bool {
+	rel, err := filepath.Rel(root, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+func copyDir(src, dst string) (int, error) {
+	n := 0
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if err := copyFile(p, target); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,102 @@
+package ssg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	p, err := expand("/blog/{slug}", Params{"slug": "hello world"})
+	if err != nil || p != "/blog/hello%20world" {
+		t.Fatalf("got %q, %v", p, err)
+	}
+
+	for _, v := range []string{"", ".", "..", "../../../pwned", "a/b", `a\b`} {
+		if _, err := expand("/blog/{slug}", Params{"slug": v}); err == nil {
+			t.Errorf("accepted %q", v)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	params, ok := match("/blog/{slug}", "/blog/hello%20world/")
+	if !ok || params["slug"] != "hello world" {
+		t.Fatalf("got %v, %v", params, ok)
+	}
+	for _, p := range []string{"/blog", "/blog/a/b", "/news/a", "/blog/%2E%2E"} {
+		if _, ok := match("/blog/{slug}", p); ok {
+			t.Errorf("matched %q", p)
+		}
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	for _, c := range []struct{ pattern, p, want string }{
+		{"/", "/", "index.html"},
+		{"/about", "/about", "about/index.html"},
+		{"/404.html", "/404.html", "404.html"},
+		{"/releases/{version}", "/releases/v1.2", "releases/v1.2/index.html"},
+	} {
+		if got := outputFile(c.pattern, c.p); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.p, got, c.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	out := t.TempDir()
+	fail := errors.New("fail")
+
+	s := New()
+	s.Page("/", func() string { return "<p>home</p>" })
+	s.Page("/404.html", func() string { return "<p>missing</p>" })
+	s.Generate("/releases/{version}", func() ([]Params, error) {
+		return []Params{{"version": "v1.2"}, {"version": "../../pwned"}, {"version": "broken"}}, nil
+	}, func(p Params) (string, error) {
+		if p["version"] == "broken" {
+			return "", fail
+		}
+		return "<p>" + p["version"] + "</p>", nil
+	})
+	s.Generate("/tags/{tag}", func() ([]Params, error) { return nil, fail }, nil)
+
+	report, err := s.Build(Options{Out: out, BaseURL: "https://example.com/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Pages) != 3 {
+		t.Errorf("built %v", report.Pages)
+	}
+	if len(report.Errors) != 3 || report.Err() == nil {
+		t.Errorf("collected %v", report.Errors)
+	}
+	if !errors.Is(report.Errors[1], fail) {
+		t.Errorf("render error lost: %v", report.Errors[1])
+	}
+
+	for _, name := range []string{"index.html", "404.html", "releases/v1.2/index.html"} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(out), "pwned")); err == nil {
+		t.Error("wrote outside the output directory")
+	}
+
+	sitemap, err := os.ReadFile(filepath.Join(out, "sitemap.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<loc>https://example.com/</loc>", "<loc>https://example.com/releases/v1.2</loc>"} {
+		if !strings.Contains(string(sitemap), want) {
+			t.Errorf("sitemap lacks %s", want)
+		}
+	}
+	if strings.Contains(string(sitemap), "404.html") {
+		t.Error("sitemap lists 404.html")
+	}
+}
@@ -0,0 +1,212 @@
+package svg
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/SVG/Attribute
+
+import (
+	"strings"
+)
+
+/*
+ * ViewBox maps user units onto the viewport. The zero value is left out.
+ *
+ *	ViewBox{Width: 24, Height: 24} // viewBox="0 0 24 24"
+ */
+type ViewBox struct {
+	MinX, MinY, Width, Height float64
+}
+
+func (v ViewBox) String() string {
+	if v == (ViewBox{}) {
+		return ""
+	}
+	return numbers([]float64{v.MinX, v.MinY, v.Width, v.Height})
+}
+
+/*
+ * Transform lists transform functions, applied right to left as SVG
+ * does. The zero value is the identity; each method returns a copy.
+ *
+ *	Transform{}.Translate(12, 12).Rotate(45) // transform="translate(12 12) rotate(45)"
+ */
+type Transform struct {
+	s string
+}
+
+func (t Transform) String() string { return t.s }
+
+func (t Transform) add(name string, args ...float64) Transform {
+	f := name + "(" + numbers(args) + ")"
+	if t.s != "" {
+		f = t.s + " " + f
+	}
+	return Transform{f}
+}
+
+func (t Transform) Translate(x, y float64) Transform { return t.add("translate", x, y) }
+
+func (t Transform) Scale(x, y float64) Transform { return t.add("scale", x, y) }
+
+// Rotate by degrees about the origin
+func (t Transform) Rotate(a float64) Transform { return t.add("rotate", a) }
+
+// RotateAround rotates by degrees about (x, y)
+func (t Transform) RotateAround(a, x, y float64) Transform { return t.add("rotate", a, x, y) }
+
+func (t Transform) SkewX(a float64) Transform { return t.add("skewX", a) }
+
+func (t Transform) SkewY(a float64) Transform { return t.add("skewY", a) }
+
+func (t Transform) Matrix(a, b, c, d, e, f float64) Transform {
+	return t.add("matrix", a, b, c, d, e, f)
+}
+
+/*
+ * PathData builds the d attribute of a path. The zero value is empty;
+ * each method appends a command to a copy. The To methods take absolute
+ * coordinates, the By methods offsets from the current point.
+ *
+ *	PathData{}.MoveTo(2, 12).LineTo(12, 2).LineTo(22, 12).Close() // d="M2 12 L12 2 L22 12 Z"
+ */
+type PathData struct {
+	s string
+}
+
+func (p PathData) String() string { return p.s }
+
+func (p PathData) add(command string, args ...float64) PathData {
+	c := command
+	if len(args) > 0 {
+		c += numbers(args)
+	}
+	if p.s != "" {
+		c = p.s + " " + c
+	}
+	return PathData{c}
+}
+
+func (p PathData) MoveTo(x, y float64) PathData   { return p.add("M", x, y) }
+func (p PathData) MoveBy(dx, dy float64) PathData { return p.add("m", dx, dy) }
+
+func (p PathData) LineTo(x, y float64) PathData   { return p.add("L", x, y) }
+func (p PathData) LineBy(dx, dy float64) PathData { return p.add("l", dx, dy) }
+
+func (p PathData) HorizontalTo(x float64) PathData  { return p.add("H", x) }
+func (p PathData) HorizontalBy(dx float64) PathData { return p.add("h", dx) }
+
+func (p PathData) VerticalTo(y float64) PathData  { return p.add("V", y) }
+func (p PathData) VerticalBy(dy float64) PathData { return p.add("v", dy) }
+
+// CurveTo draws a cubic Bézier curve with control points (x1, y1) and (x2, y2)
+func (p PathData) CurveTo(x1, y1, x2, y2, x, y float64) PathData {
+	return p.add("C", x1, y1, x2, y2, x, y)
+}
+func (p PathData) CurveBy(dx1, dy1, dx2, dy2, dx, dy float64) PathData {
+	return p.add("c", dx1, dy1, dx2, dy2, dx, dy)
+}
+
+// SmoothCurveTo continues a cubic curve, mirroring its last control point
+func (p PathData) SmoothCurveTo(x2, y2, x, y float64) PathData { return p.add("S", x2, y2, x, y) }
+func (p PathData) SmoothCurveBy(dx2, dy2, dx, dy float64) PathData {
+	return p.add("s", dx2, dy2, dx, dy)
+}
+
+// QuadTo draws a quadratic Bézier curve with control point (x1, y1)
+func (p PathData) QuadTo(x1, y1, x, y float64) PathData { return p.add("Q", x1, y1, x, y) }
+func (p PathData) QuadBy(dx1, dy1, dx, dy float64) PathData {
+	return p.add("q", dx1, dy1, dx, dy)
+}
+
+// SmoothQuadTo continues a quadratic curve, mirroring its control point
+func (p PathData) SmoothQuadTo(x, y float64) PathData   { return p.add("T", x, y) }
+func (p PathData) SmoothQuadBy(dx, dy float64) PathData { return p.add("t", dx, dy) }
+
+// ArcTo draws an elliptical arc with radii (rx, ry) rotated by rotation degrees
+func (p PathData) ArcTo(rx, ry, rotation float64, large, sweep bool, x, y float64) PathData {
+	return p.add("A", rx, ry, rotation, flag(large), flag(sweep), x, y)
+}
+func (p PathData) ArcBy(rx, ry, rotation float64, large, sweep bool, dx, dy float64) PathData {
+	return p.add("a", rx, ry, rotation, flag(large), flag(sweep), dx, dy)
+}
+
+// Close draws a line back to the start of the subpath
+func (p PathData) Close() PathData { return p.add("Z") }
+
+func flag(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Point in user units
+type Point struct {
+	X, Y float64
+}
+
+// Points of a polyline or polygon
+type Points []Point
+
+func (ps Points) String() string {
+	parts := make([]string, len(ps))
+	for i, p := range ps {
+		parts[i] = number(p.X) + "," + number(p.Y)
+	}
+	return strings.Join(parts, " ")
+}
+
+/*
+ * Options
+ *   Coordinate systems of gradients, patterns, clip paths and masks
+ */
+type unitsOption struct{ string }
+
+func (o unitsOption) String() string { return o.string }
+
+func unitsOptionUserSpaceOnUse() unitsOption {
+	return unitsOption{"userSpaceOnUse"}
+}
+
+func unitsOptionObjectBoundingBox() unitsOption {
+	return unitsOption{"objectBoundingBox"}
+}
+
+type unitsOptions struct {
+	UserSpaceOnUse    func() unitsOption
+	ObjectBoundingBox func() unitsOption
+}
+
+var UnitsOptions = unitsOptions{
+	UserSpaceOnUse:    unitsOptionUserSpaceOnUse,
+	ObjectBoundingBox: unitsOptionObjectBoundingBox,
+}
+
+/*
+ * How a gradient fills beyond its ends
+ */
+type spreadMethodOption struct{ string }
+
+func (o spreadMethodOption) String() string { return o.string }
+
+func spreadMethodOptionPad() spreadMethodOption {
+	return spreadMethodOption{"pad"}
+}
+
+func spreadMethodOptionReflect() spreadMethodOption {
+	return spreadMethodOption{"reflect"}
+}
+
+func spreadMethodOptionRepeat() spreadMethodOption {
+	return spreadMethodOption{"repeat"}
+}
+
+type spreadMethodOptions struct {
+	Pad     func() spreadMethodOption
+	Reflect func() spreadMethodOption
+	Repeat  func() spreadMethodOption
+}
+
+var SpreadMethodOptions = spreadMethodOptions{
+	Pad:     spreadMethodOptionPad,
+	Reflect: spreadMethodOptionReflect,
+	Repeat:  spreadMethodOptionRepeat,
+}
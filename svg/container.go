@@ -0,0 +1,234 @@
+package svg
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/SVG/Element#container_elements
+
+import (
+	. "github.com/bitpartio/Mx/utils"
+)
+
+// Namespace of SVG elements, written on the root so the markup also stands
+// alone as a file
+const Namespace = "http://www.w3.org/2000/svg"
+
+/*
+ * Document prefixes an svg element with the XML declaration, for serving
+ * it as an image/svg+xml file
+ */
+func Document(svg string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + svg
+}
+
+/*
+ * The root of an SVG fragment, defining a viewport and coordinate system.
+ * Width and Height take CSS lengths, e.g. "24" or "100%".
+ */
+type SvgProps struct {
+	GlobalProps
+
+	Height              string
+	PreserveAspectRatio string
+	ViewBox             ViewBox
+	Width               string
+	X                   float64
+	Y                   float64
+
+	InnerHTML string
+}
+
+func Svg(props SvgProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"height":              BuildProp("height", props.Height),
+		"preserveaspectratio": BuildProp("preserveAspectRatio", props.PreserveAspectRatio),
+		"viewbox":             BuildProp("viewBox", props.ViewBox.String()),
+		"width":               BuildProp("width", props.Width),
+		"x":                   buildNumberProp("x", props.X),
+		"xmlns":               BuildProp("xmlns", Namespace),
+		"y":                   buildNumberProp("y", props.Y),
+	}
+
+	return build("svg", values, props.InnerHTML)
+}
+
+/*
+ * Groups elements, so attributes and transforms apply to all of them.
+ */
+type GProps struct {
+	GlobalProps
+
+	InnerHTML string
+}
+
+func G(props GProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+	}
+
+	return build("g", values, props.InnerHTML)
+}
+
+/*
+ * Holds elements drawn only where referenced, such as gradients, clip
+ * paths and symbols.
+ */
+type DefsProps struct {
+	GlobalProps
+
+	InnerHTML string
+}
+
+func Defs(props DefsProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+	}
+
+	return build("defs", values, props.InnerHTML)
+}
+
+/*
+ * Defines a graphic, such as an icon, drawn by use elements. Its viewBox
+ * scales it to each use's size.
+ */
+type SymbolProps struct {
+	GlobalProps
+
+	Height              string
+	PreserveAspectRatio string
+	ViewBox             ViewBox
+	Width               string
+
+	InnerHTML string
+}
+
+func Symbol(props SymbolProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"height":              BuildProp("height", props.Height),
+		"preserveaspectratio": BuildProp("preserveAspectRatio", props.PreserveAspectRatio),
+		"viewbox":             BuildProp("viewBox", props.ViewBox.String()),
+		"width":               BuildProp("width", props.Width),
+	}
+
+	return build("symbol", values, props.InnerHTML)
+}
+
+/*
+ * Draws a copy of the element Href references, e.g. "#icon-close" or
+ * "/icons.svg#close".
+ */
+type UseProps struct {
+	GlobalProps
+
+	Height float64
+	Href   string
+	Width  float64
+	X      float64
+	Y      float64
+
+	InnerHTML string
+}
+
+func Use(props UseProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"height": buildNumberProp("height", props.Height),
+		"href":   BuildProp("href", props.Href),
+		"width":  buildNumberProp("width", props.Width),
+		"x":      buildNumberProp("x", props.X),
+		"y":      buildNumberProp("y", props.Y),
+	}
+
+	return build("use", values, props.InnerHTML)
+}
+
+/*
+ * A hyperlink around graphics.
+ */
+type AProps struct {
+	GlobalProps
+
+	Href   string
+	Target string
+
+	InnerHTML string
+}
+
+func A(props AProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"href":   BuildProp("href", props.Href),
+		"target": BuildProp("target", props.Target),
+	}
+
+	return build("a", values, props.InnerHTML)
+}
+
+/*
+ * The accessible name of its parent, shown by browsers as a tooltip.
+ */
+type TitleProps struct {
+	GlobalProps
+
+	InnerHTML string
+}
+
+func Title(props TitleProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+	}
+
+	return build("title", values, props.InnerHTML)
+}
+
+/*
+ * The accessible description of its parent.
+ */
+type DescProps struct {
+	GlobalProps
+
+	InnerHTML string
+}
+
+func Desc(props DescProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+	}
+
+	return build("desc", values, props.InnerHTML)
+}
+
+/*
+ * Defines a graphic drawn at the vertices of paths, lines and polygons
+ * that reference it with marker-start, marker-mid or marker-end.
+ */
+type MarkerProps struct {
+	GlobalProps
+
+	MarkerHeight *float64 // unset is the SVG default of 3
+	MarkerWidth  *float64
+	Orient       string // "auto", "auto-start-reverse" or an angle
+	RefX         float64
+	RefY         float64
+	ViewBox      ViewBox
+
+	InnerHTML string
+}
+
+func Marker(props MarkerProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"markerheight": BuildFloatProp("markerHeight", props.MarkerHeight),
+		"markerwidth":  BuildFloatProp("markerWidth", props.MarkerWidth),
+		"orient":       BuildProp("orient", props.Orient),
+		"refx":         buildNumberProp("refX", props.RefX),
+		"refy":         buildNumberProp("refY", props.RefY),
+		"viewbox":      BuildProp("viewBox", props.ViewBox.String()),
+	}
+
+	return build("marker", values, props.InnerHTML)
+}
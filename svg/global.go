@@ -0,0 +1,350 @@
+/*
+ * Package svg builds inline SVG with the same typed props as the elements
+ * package: icons, charts and illustrations. Attribute names keep their SVG
+ * case (viewBox, gradientUnits) and empty elements close themselves, so
+ * the markup reads the same embedded in HTML or served as an .svg file.
+ *
+ *	svg.Svg(svg.SvgProps{
+ *		ViewBox: svg.ViewBox{Width: 24, Height: 24},
+ *		GlobalProps: svg.GlobalProps{
+ *			Fill: "none", Stroke: "currentColor", StrokeWidth: utils.Ptr(2.0),
+ *			StrokeLinecap: svg.GlobalOptions.StrokeLinecap.Round,
+ *		},
+ *		InnerHTML: utils.Stack(
+ *			svg.Circle(svg.CircleProps{Cx: 12, Cy: 12, R: 10}),
+ *			svg.Path(svg.PathProps{D: svg.PathData{}.MoveTo(12, 8).VerticalTo(12)}),
+ *		),
+ *	})
+ *
+ * Coordinates and lengths are numbers in user units; zero values are left
+ * out, which SVG reads as 0. Attributes defaulting to something else, such
+ * as opacity, stroke-width and stroke-miterlimit, are pointers so that an
+ * explicit 0 is still written.
+ */
+package svg
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/SVG/Attribute#presentation_attributes
+
+import (
+	"strconv"
+	"strings"
+
+	. "github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * Props
+ *   Core and presentation attributes shared by every element
+ */
+type GlobalProps struct {
+	Aria     AriaRoles
+	Data     DataValues
+	Htmx     HtmxProps
+	Class    []string
+	ID       string
+	Lang     string
+	Style    string
+	Tabindex string
+	// Presentation
+	ClipPath         string // e.g. "url(#clip)"
+	ClipRule         func() fillRuleOption
+	Color            string
+	Display          string
+	DominantBaseline string
+	Fill             string
+	FillOpacity      *float64
+	FillRule         func() fillRuleOption
+	Filter           string
+	FontFamily       string
+	FontSize         string
+	FontWeight       string
+	MarkerEnd        string
+	MarkerMid        string
+	MarkerStart      string
+	Mask             string
+	Opacity          *float64
+	PointerEvents    string
+	Stroke           string
+	StrokeDasharray  []float64
+	StrokeDashoffset float64
+	StrokeLinecap    func() strokeLinecapOption
+	StrokeLinejoin   func() strokeLinejoinOption
+	StrokeMiterlimit *float64
+	StrokeOpacity    *float64
+	StrokeWidth      *float64
+	TextAnchor       func() textAnchorOption
+	Transform        Transform
+	VectorEffect     string
+	Visibility       string
+}
+
+/*
+ * Options
+ *   Encapsulate options into predetermined functions
+ */
+type globalOptions struct {
+	FillRule       fillRuleOptions
+	StrokeLinecap  strokeLinecapOptions
+	StrokeLinejoin strokeLinejoinOptions
+	TextAnchor     textAnchorOptions
+}
+
+var GlobalOptions globalOptions
+
+// Initialize
+func init() {
+	GlobalOptions = globalOptions{
+		FillRule: fillRuleOptions{
+			Nonzero: fillRuleOptionNonzero,
+			Evenodd: fillRuleOptionEvenodd,
+		},
+		StrokeLinecap: strokeLinecapOptions{
+			Butt:   strokeLinecapOptionButt,
+			Round:  strokeLinecapOptionRound,
+			Square: strokeLinecapOptionSquare,
+		},
+		StrokeLinejoin: strokeLinejoinOptions{
+			Miter:     strokeLinejoinOptionMiter,
+			MiterClip: strokeLinejoinOptionMiterClip,
+			Round:     strokeLinejoinOptionRound,
+			Bevel:     strokeLinejoinOptionBevel,
+			Arcs:      strokeLinejoinOptionArcs,
+		},
+		TextAnchor: textAnchorOptions{
+			Start:  textAnchorOptionStart,
+			Middle: textAnchorOptionMiddle,
+			End:    textAnchorOptionEnd,
+		},
+	}
+}
+
+/*
+ * FillRule, also used by clip-rule
+ */
+type fillRuleOption struct{ string }
+
+func (o fillRuleOption) String() string { return o.string }
+
+func fillRuleOptionNonzero() fillRuleOption {
+	return fillRuleOption{"nonzero"}
+}
+
+func fillRuleOptionEvenodd() fillRuleOption {
+	return fillRuleOption{"evenodd"}
+}
+
+type fillRuleOptions struct {
+	Nonzero func() fillRuleOption
+	Evenodd func() fillRuleOption
+}
+
+/*
+ * StrokeLinecap
+ */
+type strokeLinecapOption struct{ string }
+
+func (o strokeLinecapOption) String() string { return o.string }
+
+func strokeLinecapOptionButt() strokeLinecapOption {
+	return strokeLinecapOption{"butt"}
+}
+
+func strokeLinecapOptionRound() strokeLinecapOption {
+	return strokeLinecapOption{"round"}
+}
+
+func strokeLinecapOptionSquare() strokeLinecapOption {
+	return strokeLinecapOption{"square"}
+}
+
+type strokeLinecapOptions struct {
+	Butt   func() strokeLinecapOption
+	Round  func() strokeLinecapOption
+	Square func() strokeLinecapOption
+}
+
+/*
+ * StrokeLinejoin
+ */
+type strokeLinejoinOption struct{ string }
+
+func (o strokeLinejoinOption) String() string { return o.string }
+
+func strokeLinejoinOptionMiter() strokeLinejoinOption {
+	return strokeLinejoinOption{"miter"}
+}
+
+func strokeLinejoinOptionMiterClip() strokeLinejoinOption {
+	return strokeLinejoinOption{"miter-clip"}
+}
+
+func strokeLinejoinOptionRound() strokeLinejoinOption {
+	return strokeLinejoinOption{"round"}
+}
+
+func strokeLinejoinOptionBevel() strokeLinejoinOption {
+	return strokeLinejoinOption{"bevel"}
+}
+
+func strokeLinejoinOptionArcs() strokeLinejoinOption {
+	return strokeLinejoinOption{"arcs"}
+}
+
+type strokeLinejoinOptions struct {
+	Miter     func() strokeLinejoinOption
+	MiterClip func() strokeLinejoinOption
+	Round     func() strokeLinejoinOption
+	Bevel     func() strokeLinejoinOption
+	Arcs      func() strokeLinejoinOption
+}
+
+/*
+ * TextAnchor
+ */
+type textAnchorOption struct{ string }
+
+func (o textAnchorOption) String() string { return o.string }
+
+func textAnchorOptionStart() textAnchorOption {
+	return textAnchorOption{"start"}
+}
+
+func textAnchorOptionMiddle() textAnchorOption {
+	return textAnchorOption{"middle"}
+}
+
+func textAnchorOptionEnd() textAnchorOption {
+	return textAnchorOption{"end"}
+}
+
+type textAnchorOptions struct {
+	Start  func() textAnchorOption
+	Middle func() textAnchorOption
+	End    func() textAnchorOption
+}
+
+/*
+ * Build
+ */
+func buildGlobalProps(props GlobalProps) map[string]interface{} {
+	values := map[string]interface{}{}
+
+	values["id"] = BuildProp("id", props.ID)
+	values["class"] = BuildPropListWithSpaces("class", props.Class)
+	values["lang"] = BuildProp("lang", props.Lang)
+	values["style"] = BuildProp("style", props.Style)
+	values["tabindex"] = BuildProp("tabindex", props.Tabindex)
+
+	// presentation
+	values["clip-path"] = BuildProp("clip-path", props.ClipPath)
+	if props.ClipRule != nil {
+		values["clip-rule"] = BuildProp("clip-rule", props.ClipRule().String())
+	}
+	values["color"] = BuildProp("color", props.Color)
+	values["display"] = BuildProp("display", props.Display)
+	values["dominant-baseline"] = BuildProp("dominant-baseline", props.DominantBaseline)
+	values["fill"] = BuildProp("fill", props.Fill)
+	values["fill-opacity"] = BuildFloatProp("fill-opacity", props.FillOpacity)
+	if props.FillRule != nil {
+		values["fill-rule"] = BuildProp("fill-rule", props.FillRule().String())
+	}
+	values["filter"] = BuildProp("filter", props.Filter)
+	values["font-family"] = BuildProp("font-family", props.FontFamily)
+	values["font-size"] = BuildProp("font-size", props.FontSize)
+	values["font-weight"] = BuildProp("font-weight", props.FontWeight)
+	values["marker-end"] = BuildProp("marker-end", props.MarkerEnd)
+	values["marker-mid"] = BuildProp("marker-mid", props.MarkerMid)
+	values["marker-start"] = BuildProp("marker-start", props.MarkerStart)
+	values["mask"] = BuildProp("mask", props.Mask)
+	values["opacity"] = BuildFloatProp("opacity", props.Opacity)
+	values["pointer-events"] = BuildProp("pointer-events", props.PointerEvents)
+	values["stroke"] = BuildProp("stroke", props.Stroke)
+	values["stroke-dasharray"] = BuildProp("stroke-dasharray", numbers(props.StrokeDasharray))
+	values["stroke-dashoffset"] = buildNumberProp("stroke-dashoffset", props.StrokeDashoffset)
+	if props.StrokeLinecap != nil {
+		values["stroke-linecap"] = BuildProp("stroke-linecap", props.StrokeLinecap().String())
+	}
+	if props.StrokeLinejoin != nil {
+		values["stroke-linejoin"] = BuildProp("stroke-linejoin", props.StrokeLinejoin().String())
+	}
+	values["stroke-miterlimit"] = BuildFloatProp("stroke-miterlimit", props.StrokeMiterlimit)
+	values["stroke-opacity"] = BuildFloatProp("stroke-opacity", props.StrokeOpacity)
+	values["stroke-width"] = BuildFloatProp("stroke-width", props.StrokeWidth)
+	if props.TextAnchor != nil {
+		values["text-anchor"] = BuildProp("text-anchor", props.TextAnchor().String())
+	}
+	values["transform"] = BuildProp("transform", props.Transform.String())
+	values["vector-effect"] = BuildProp("vector-effect", props.VectorEffect)
+	values["visibility"] = BuildProp("visibility", props.Visibility)
+
+	values["data"] = BuildDataValues(props.Data)
+	values["aria"] = BuildAriaRoles(props.Aria)
+	values["htmx"] = BuildHtmxProps(props.Htmx)
+
+	return values
+}
+
+/*
+ * BuildGlobalProps
+ */
+func BuildGlobalProps(props GlobalProps) string {
+	values := buildGlobalProps(props)
+
+	var s strings.Builder
+	for _, key := range []string{
+		"id", "class", "lang", "style", "tabindex",
+		"clip-path", "clip-rule", "color", "display", "dominant-baseline",
+		"fill", "fill-opacity", "fill-rule", "filter",
+		"font-family", "font-size", "font-weight",
+		"marker-end", "marker-mid", "marker-start", "mask", "opacity", "pointer-events",
+		"stroke", "stroke-dasharray", "stroke-dashoffset", "stroke-linecap",
+		"stroke-linejoin", "stroke-miterlimit", "stroke-opacity", "stroke-width",
+		"text-anchor", "transform", "vector-effect", "visibility",
+		"data", "aria", "htmx",
+	} {
+		if v, _ := values[key].(string); v != "" {
+			s.WriteString(v)
+			s.WriteString(" ")
+		}
+	}
+	return strings.TrimSuffix(s.String(), " ")
+}
+
+// buildNumberProp leaves out zero, so it only writes attributes where a
+// missing value means 0; those with another default are *float64 and go
+// through BuildFloatProp
+func buildNumberProp(name string, prop float64) string {
+	if prop == 0 {
+		return ""
+	}
+	return BuildProp(name, number(prop))
+}
+
+func number(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func numbers(fs []float64) string {
+	parts := make([]string, len(fs))
+	for i, f := range fs {
+		parts[i] = number(f)
+	}
+	return strings.Join(parts, " ")
+}
+
+/*
+ * build renders an element, closing it itself when it has no content as
+ * XML allows inside HTML: <circle r="4" />
+ */
+func build(tag string, values map[string]interface{}, innerHTML string) string {
+	if innerHTML != "" {
+		values["innerhtml"] = innerHTML
+	}
+
+	m := BuildMarkup(tag, values)
+
+	t := Mx(m)
+
+	s := Render(t, values)
+	return s
+}
@@ -0,0 +1,221 @@
+package svg
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/SVG/Element#gradient_elements
+
+import (
+	. "github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * Defines a gradient along the line from (X1, Y1) to (X2, Y2), referenced
+ * as a fill or stroke with "url(#id)". Coordinates take numbers or
+ * percentages.
+ */
+type LinearGradientProps struct {
+	GlobalProps
+
+	GradientTransform Transform
+	GradientUnits     func() unitsOption
+	Href              string
+	SpreadMethod      func() spreadMethodOption
+	X1                string
+	X2                string
+	Y1                string
+	Y2                string
+
+	InnerHTML string
+}
+
+func LinearGradient(props LinearGradientProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"gradienttransform": BuildProp("gradientTransform", props.GradientTransform.String()),
+		"href":              BuildProp("href", props.Href),
+		"x1":                BuildProp("x1", props.X1),
+		"x2":                BuildProp("x2", props.X2),
+		"y1":                BuildProp("y1", props.Y1),
+		"y2":                BuildProp("y2", props.Y2),
+	}
+
+	if props.GradientUnits != nil {
+		values["gradientunits"] = BuildProp("gradientUnits", props.GradientUnits().String())
+	}
+
+	if props.SpreadMethod != nil {
+		values["spreadmethod"] = BuildProp("spreadMethod", props.SpreadMethod().String())
+	}
+
+	return build("linearGradient", values, props.InnerHTML)
+}
+
+/*
+ * Defines a gradient radiating from the focal point (Fx, Fy) to the circle
+ * of radius R around (Cx, Cy).
+ */
+type RadialGradientProps struct {
+	GlobalProps
+
+	Cx                string
+	Cy                string
+	Fx                string
+	Fy                string
+	GradientTransform Transform
+	GradientUnits     func() unitsOption
+	Href              string
+	R                 string
+	SpreadMethod      func() spreadMethodOption
+
+	InnerHTML string
+}
+
+func RadialGradient(props RadialGradientProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"cx":                BuildProp("cx", props.Cx),
+		"cy":                BuildProp("cy", props.Cy),
+		"fx":                BuildProp("fx", props.Fx),
+		"fy":                BuildProp("fy", props.Fy),
+		"gradienttransform": BuildProp("gradientTransform", props.GradientTransform.String()),
+		"href":              BuildProp("href", props.Href),
+		"r":                 BuildProp("r", props.R),
+	}
+
+	if props.GradientUnits != nil {
+		values["gradientunits"] = BuildProp("gradientUnits", props.GradientUnits().String())
+	}
+
+	if props.SpreadMethod != nil {
+		values["spreadmethod"] = BuildProp("spreadMethod", props.SpreadMethod().String())
+	}
+
+	return build("radialGradient", values, props.InnerHTML)
+}
+
+/*
+ * A color of a gradient, at Offset along it ("0.5" or "50%").
+ */
+type StopProps struct {
+	GlobalProps
+
+	Offset      string
+	StopColor   string
+	StopOpacity *float64
+}
+
+func Stop(props StopProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"offset":       BuildProp("offset", props.Offset),
+		"stop-color":   BuildProp("stop-color", props.StopColor),
+		"stop-opacity": BuildFloatProp("stop-opacity", props.StopOpacity),
+	}
+
+	return build("stop", values, "")
+}
+
+/*
+ * Defines a tile repeated to fill or stroke shapes.
+ */
+type PatternProps struct {
+	GlobalProps
+
+	Height              float64
+	Href                string
+	PatternContentUnits func() unitsOption
+	PatternTransform    Transform
+	PatternUnits        func() unitsOption
+	ViewBox             ViewBox
+	Width               float64
+	X                   float64
+	Y                   float64
+
+	InnerHTML string
+}
+
+func Pattern(props PatternProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"height":           buildNumberProp("height", props.Height),
+		"href":             BuildProp("href", props.Href),
+		"patterntransform": BuildProp("patternTransform", props.PatternTransform.String()),
+		"viewbox":          BuildProp("viewBox", props.ViewBox.String()),
+		"width":            buildNumberProp("width", props.Width),
+		"x":                buildNumberProp("x", props.X),
+		"y":                buildNumberProp("y", props.Y),
+	}
+
+	if props.PatternContentUnits != nil {
+		values["patterncontentunits"] = BuildProp("patternContentUnits", props.PatternContentUnits().String())
+	}
+
+	if props.PatternUnits != nil {
+		values["patternunits"] = BuildProp("patternUnits", props.PatternUnits().String())
+	}
+
+	return build("pattern", values, props.InnerHTML)
+}
+
+/*
+ * Defines the region shapes referencing it with clip-path are drawn in.
+ */
+type ClipPathProps struct {
+	GlobalProps
+
+	ClipPathUnits func() unitsOption
+
+	InnerHTML string
+}
+
+func ClipPath(props ClipPathProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+	}
+
+	if props.ClipPathUnits != nil {
+		values["clippathunits"] = BuildProp("clipPathUnits", props.ClipPathUnits().String())
+	}
+
+	return build("clipPath", values, props.InnerHTML)
+}
+
+/*
+ * Defines a mask whose luminance sets the opacity of shapes referencing it
+ * with mask. Region coordinates take numbers or percentages.
+ */
+type MaskProps struct {
+	GlobalProps
+
+	Height           string
+	MaskContentUnits func() unitsOption
+	MaskUnits        func() unitsOption
+	Width            string
+	X                string
+	Y                string
+
+	InnerHTML string
+}
+
+func Mask(props MaskProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"height": BuildProp("height", props.Height),
+		"width":  BuildProp("width", props.Width),
+		"x":      BuildProp("x", props.X),
+		"y":      BuildProp("y", props.Y),
+	}
+
+	if props.MaskContentUnits != nil {
+		values["maskcontentunits"] = BuildProp("maskContentUnits", props.MaskContentUnits().String())
+	}
+
+	if props.MaskUnits != nil {
+		values["maskunits"] = BuildProp("maskUnits", props.MaskUnits().String())
+	}
+
+	return build("mask", values, props.InnerHTML)
+}
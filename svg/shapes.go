@@ -0,0 +1,238 @@
+package svg
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/SVG/Element#basic_shapes
+
+import (
+	. "github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * Draws any shape from path data. See PathData.
+ */
+type PathProps struct {
+	GlobalProps
+
+	D          PathData
+	PathLength float64
+
+	InnerHTML string
+}
+
+func Path(props PathProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"d":          BuildProp("d", props.D.String()),
+		"pathlength": buildNumberProp("pathLength", props.PathLength),
+	}
+
+	return build("path", values, props.InnerHTML)
+}
+
+/*
+ * Draws a rectangle, with corners rounded by Rx and Ry.
+ */
+type RectProps struct {
+	GlobalProps
+
+	Height float64
+	Rx     float64
+	Ry     float64
+	Width  float64
+	X      float64
+	Y      float64
+
+	InnerHTML string
+}
+
+func Rect(props RectProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"height": buildNumberProp("height", props.Height),
+		"rx":     buildNumberProp("rx", props.Rx),
+		"ry":     buildNumberProp("ry", props.Ry),
+		"width":  buildNumberProp("width", props.Width),
+		"x":      buildNumberProp("x", props.X),
+		"y":      buildNumberProp("y", props.Y),
+	}
+
+	return build("rect", values, props.InnerHTML)
+}
+
+/*
+ * Draws a circle centered on (Cx, Cy).
+ */
+type CircleProps struct {
+	GlobalProps
+
+	Cx float64
+	Cy float64
+	R  float64
+
+	InnerHTML string
+}
+
+func Circle(props CircleProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"cx": buildNumberProp("cx", props.Cx),
+		"cy": buildNumberProp("cy", props.Cy),
+		"r":  buildNumberProp("r", props.R),
+	}
+
+	return build("circle", values, props.InnerHTML)
+}
+
+/*
+ * Draws an ellipse centered on (Cx, Cy).
+ */
+type EllipseProps struct {
+	GlobalProps
+
+	Cx float64
+	Cy float64
+	Rx float64
+	Ry float64
+
+	InnerHTML string
+}
+
+func Ellipse(props EllipseProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"cx": buildNumberProp("cx", props.Cx),
+		"cy": buildNumberProp("cy", props.Cy),
+		"rx": buildNumberProp("rx", props.Rx),
+		"ry": buildNumberProp("ry", props.Ry),
+	}
+
+	return build("ellipse", values, props.InnerHTML)
+}
+
+/*
+ * Draws a line from (X1, Y1) to (X2, Y2).
+ */
+type LineProps struct {
+	GlobalProps
+
+	X1 float64
+	X2 float64
+	Y1 float64
+	Y2 float64
+
+	InnerHTML string
+}
+
+func Line(props LineProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"x1": buildNumberProp("x1", props.X1),
+		"x2": buildNumberProp("x2", props.X2),
+		"y1": buildNumberProp("y1", props.Y1),
+		"y2": buildNumberProp("y2", props.Y2),
+	}
+
+	return build("line", values, props.InnerHTML)
+}
+
+/*
+ * Draws straight lines through the points, left open.
+ */
+type PolylineProps struct {
+	GlobalProps
+
+	Points Points
+
+	InnerHTML string
+}
+
+func Polyline(props PolylineProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"points": BuildProp("points", props.Points.String()),
+	}
+
+	return build("polyline", values, props.InnerHTML)
+}
+
+/*
+ * Draws a closed shape through the points.
+ */
+type PolygonProps struct {
+	GlobalProps
+
+	Points Points
+
+	InnerHTML string
+}
+
+func Polygon(props PolygonProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"points": BuildProp("points", props.Points.String()),
+	}
+
+	return build("polygon", values, props.InnerHTML)
+}
+
+/*
+ * Embeds a raster or SVG image.
+ */
+type ImageProps struct {
+	GlobalProps
+
+	Height              float64
+	Href                string
+	PreserveAspectRatio string
+	Width               float64
+	X                   float64
+	Y                   float64
+}
+
+func Image(props ImageProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"height":              buildNumberProp("height", props.Height),
+		"href":                BuildProp("href", props.Href),
+		"preserveaspectratio": BuildProp("preserveAspectRatio", props.PreserveAspectRatio),
+		"width":               buildNumberProp("width", props.Width),
+		"x":                   buildNumberProp("x", props.X),
+		"y":                   buildNumberProp("y", props.Y),
+	}
+
+	return build("image", values, "")
+}
+
+/*
+ * Embeds HTML, such as a wrapping label, in a region of the drawing.
+ */
+type ForeignObjectProps struct {
+	GlobalProps
+
+	Height float64
+	Width  float64
+	X      float64
+	Y      float64
+
+	InnerHTML string
+}
+
+func ForeignObject(props ForeignObjectProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"height": buildNumberProp("height", props.Height),
+		"width":  buildNumberProp("width", props.Width),
+		"x":      buildNumberProp("x", props.X),
+		"y":      buildNumberProp("y", props.Y),
+	}
+
+	return build("foreignObject", values, props.InnerHTML)
+}
@@ -0,0 +1,145 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitpartio/Mx/utils"
+)
+
+func TestAttributes(t *testing.T) {
+	d := PathData{}.MoveTo(2, 12).LineBy(10, -10).ArcTo(5, 5, 0, false, true, 22, 12).Close()
+	if got := d.String(); got != "M2 12 l10 -10 A5 5 0 0 1 22 12 Z" {
+		t.Fatalf("path data %q", got)
+	}
+	if got := (Transform{}).Translate(12, 12).Rotate(45).String(); got != "translate(12 12) rotate(45)" {
+		t.Fatalf("transform %q", got)
+	}
+	if got := (Points{{0, 0}, {1.5, 2}}).String(); got != "0,0 1.5,2" {
+		t.Fatalf("points %q", got)
+	}
+}
+
+func contains(t *testing.T, got string, wants ...string) {
+	t.Helper()
+	for _, want := range wants {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+}
+
+func TestSvg(t *testing.T) {
+	got := Svg(SvgProps{
+		ViewBox: ViewBox{Width: 24, Height: 24},
+		GlobalProps: GlobalProps{
+			Fill:          "none",
+			StrokeWidth:   utils.Ptr(2.0),
+			StrokeLinecap: GlobalOptions.StrokeLinecap.Round,
+		},
+		InnerHTML: Circle(CircleProps{Cx: 12, Cy: 12, R: 10}) +
+			LinearGradient(LinearGradientProps{GradientUnits: UnitsOptions.UserSpaceOnUse}),
+	})
+
+	contains(t, got, `<svg `, `viewBox="0 0 24 24"`, `xmlns="http://www.w3.org/2000/svg"`,
+		`fill="none"`, `stroke-width="2"`, `stroke-linecap="round"`,
+		`<circle `, `cx="12"`, `r="10"`, `/>`,
+		`<linearGradient `, `gradientUnits="userSpaceOnUse"`,
+		`</svg>`)
+	if strings.Contains(got, "</circle>") {
+		t.Errorf("empty element not self-closed: %s", got)
+	}
+}
+
+func TestSymbolUse(t *testing.T) {
+	got := Symbol(SymbolProps{
+		GlobalProps: GlobalProps{ID: "icon-close"},
+		ViewBox:     ViewBox{Width: 24, Height: 24},
+		InnerHTML:   Path(PathProps{D: PathData{}.MoveTo(6, 6).LineTo(18, 18)}),
+	})
+	contains(t, got, `<symbol `, `id="icon-close"`, `viewBox="0 0 24 24"`, `<path `, `d="M6 6 L18 18"`, `</symbol>`)
+
+	got = Use(UseProps{Href: "#icon-close", Width: 16, Height: 16})
+	contains(t, got, `<use `, `href="#icon-close"`, `width="16"`, `height="16"`, `/>`)
+	if strings.Contains(got, `x="`) || strings.Contains(got, "</use>") {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestMarker(t *testing.T) {
+	got := Marker(MarkerProps{
+		GlobalProps:  GlobalProps{ID: "arrow"},
+		MarkerHeight: utils.Ptr(0.0),
+		MarkerWidth:  utils.Ptr(6.5),
+		Orient:       "auto-start-reverse",
+		RefX:         5,
+		ViewBox:      ViewBox{Width: 10, Height: 10},
+		InnerHTML:    Path(PathProps{D: PathData{}.MoveTo(0, 0).LineTo(10, 5).LineTo(0, 10).Close()}),
+	})
+	contains(t, got, `<marker `, `id="arrow"`, `markerHeight="0"`, `markerWidth="6.5"`,
+		`orient="auto-start-reverse"`, `refX="5"`, `viewBox="0 0 10 10"`, `</marker>`)
+
+	// Unset, both keep the SVG default of 3
+	got = Marker(MarkerProps{})
+	if strings.Contains(got, "markerHeight") || strings.Contains(got, "markerWidth") {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestClipPathMask(t *testing.T) {
+	got := ClipPath(ClipPathProps{
+		GlobalProps:   GlobalProps{ID: "clip"},
+		ClipPathUnits: UnitsOptions.ObjectBoundingBox,
+		InnerHTML:     Circle(CircleProps{Cx: 0.5, Cy: 0.5, R: 0.5}),
+	})
+	contains(t, got, `<clipPath `, `id="clip"`, `clipPathUnits="objectBoundingBox"`, `r="0.5"`, `</clipPath>`)
+
+	got = Mask(MaskProps{
+		GlobalProps: GlobalProps{ID: "fade"},
+		MaskUnits:   UnitsOptions.UserSpaceOnUse,
+		X:           "0", Y: "0", Width: "100%", Height: "100%",
+		InnerHTML: Rect(RectProps{Width: 10, Height: 10, GlobalProps: GlobalProps{Fill: "white"}}),
+	})
+	contains(t, got, `<mask `, `id="fade"`, `maskUnits="userSpaceOnUse"`, `x="0"`, `width="100%"`, `fill="white"`, `</mask>`)
+}
+
+func TestText(t *testing.T) {
+	got := Text(TextProps{
+		X: 12, Y: 20, Dy: -1.5,
+		GlobalProps: GlobalProps{
+			TextAnchor:       GlobalOptions.TextAnchor.Middle,
+			DominantBaseline: "middle",
+			FontSize:         "12px",
+		},
+		InnerHTML: "42" + Tspan(TspanProps{Dx: 2, InnerHTML: "%"}),
+	})
+	contains(t, got, `<text `, `x="12"`, `y="20"`, `dy="-1.5"`, `text-anchor="middle"`,
+		`dominant-baseline="middle"`, `font-size="12px"`, `>42<tspan `, `dx="2"`, `>%</tspan>`, `</text>`)
+}
+
+func TestPresentation(t *testing.T) {
+	got := BuildGlobalProps(GlobalProps{
+		ClipPath:         "url(#clip)",
+		ClipRule:         GlobalOptions.FillRule.Evenodd,
+		FillRule:         GlobalOptions.FillRule.Nonzero,
+		Opacity:          utils.Ptr(0.0),
+		FillOpacity:      utils.Ptr(0.5),
+		StrokeDasharray:  []float64{4, 2.5},
+		StrokeDashoffset: 1,
+		StrokeLinejoin:   GlobalOptions.StrokeLinejoin.MiterClip,
+		StrokeMiterlimit: utils.Ptr(10.0),
+		StrokeWidth:      utils.Ptr(0.0),
+		Transform:        Transform{}.Scale(2, 2),
+	})
+	want := `clip-path="url(#clip)" clip-rule="evenodd" fill-opacity="0.5" fill-rule="nonzero" opacity="0" ` +
+		`stroke-dasharray="4 2.5" stroke-dashoffset="1" stroke-linejoin="miter-clip" stroke-miterlimit="10" ` +
+		`stroke-width="0" transform="scale(2 2)"`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	// Unset, stroke-width and stroke-miterlimit keep their defaults of 1 and 4
+	if got := BuildGlobalProps(GlobalProps{}); got != "" {
+		t.Errorf("got %q", got)
+	}
+}
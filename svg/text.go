@@ -0,0 +1,89 @@
+package svg
+
+// Ref: https://developer.mozilla.org/en-US/docs/Web/SVG/Element#text_content_elements
+
+import (
+	. "github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * Draws text, starting at (X, Y) and shifted by (Dx, Dy). Place it with
+ * text-anchor and dominant-baseline.
+ */
+type TextProps struct {
+	GlobalProps
+
+	Dx           float64
+	Dy           float64
+	LengthAdjust string // "spacing" or "spacingAndGlyphs"
+	TextLength   float64
+	X            float64
+	Y            float64
+
+	InnerHTML string
+}
+
+func Text(props TextProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"dx":           buildNumberProp("dx", props.Dx),
+		"dy":           buildNumberProp("dy", props.Dy),
+		"lengthadjust": BuildProp("lengthAdjust", props.LengthAdjust),
+		"textlength":   buildNumberProp("textLength", props.TextLength),
+		"x":            buildNumberProp("x", props.X),
+		"y":            buildNumberProp("y", props.Y),
+	}
+
+	return build("text", values, props.InnerHTML)
+}
+
+/*
+ * A run of text within text, styled or positioned on its own.
+ */
+type TspanProps struct {
+	GlobalProps
+
+	Dx float64
+	Dy float64
+	X  float64
+	Y  float64
+
+	InnerHTML string
+}
+
+func Tspan(props TspanProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"dx": buildNumberProp("dx", props.Dx),
+		"dy": buildNumberProp("dy", props.Dy),
+		"x":  buildNumberProp("x", props.X),
+		"y":  buildNumberProp("y", props.Y),
+	}
+
+	return build("tspan", values, props.InnerHTML)
+}
+
+/*
+ * Lays text along the path Href references.
+ */
+type TextPathProps struct {
+	GlobalProps
+
+	Href        string
+	StartOffset string // a length or percentage
+
+	InnerHTML string
+}
+
+func TextPath(props TextPathProps) string {
+	values := map[string]interface{}{
+		"global": BuildGlobalProps(props.GlobalProps),
+
+		"href":        BuildProp("href", props.Href),
+		"startoffset": BuildProp("startOffset", props.StartOffset),
+	}
+
+	return build("textPath", values, props.InnerHTML)
+}
@@ -103,6 +103,9 @@ func BuildWeekProp(name string, prop time.Time) string {
 	s.WriteString(`="`)
 	s.WriteString(strconv.Itoa(y))
 	s.WriteString("-W")
+	if w < 10 {
+		s.WriteString("0")
+	}
 	s.WriteString(strconv.Itoa(w))
 	s.WriteString(`"`)
 	return s.String()
@@ -128,11 +131,17 @@ func BuildDateTimeProp(name string, prop time.Time) string {
 	return BuildChronosProp(name, prop, time.RFC3339)
 }
 
+// BuildDateTimeLocalProp, a date and time without time zone
+func BuildDateTimeLocalProp(name string, prop time.Time) string {
+	return BuildChronosProp(name, prop, "2006-01-02T15:04")
+}
+
 // BuildProps
 func BuildProps(prefix string, attr map[string]string) string {
 	if len(attr) > 0 {
 		var attrBuilder strings.Builder
-		for key, d := range attr {
+		for _, key := range sortedKeys(attr) {
+			d := attr[key]
 			var keyBuilder strings.Builder
 			keyBuilder.WriteString(prefix)
 			keyBuilder.WriteString(key)
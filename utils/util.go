@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/valyala/fasttemplate"
@@ -43,7 +44,9 @@ func BuildMarkup(tag string, values map[string]interface{}) string {
 
 	closed := true
 
-	for p := range values {
+	// Attributes are written in a stable order so identical props always
+	// render identical markup (required for ETags and output caching)
+	for _, p := range sortedKeys(values) {
 		if p == "innerhtml" {
 			closed = false
 			continue
@@ -66,8 +69,45 @@ func BuildMarkup(tag string, values map[string]interface{}) string {
 	return s.String()
 }
 
+// sortedKeys
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // Create a reference pointer for a value
 // From: https://stackoverflow.com/questions/30716354/how-do-i-do-a-literal-int64-in-go
 func Ptr[T any](v T) *T {
 	return &v
 }
+
+/*
+ * With copies m with key set to value, so components can add attributes
+ * to the AriaRoles, DataValues or HtmxProps their caller passed without
+ * changing the caller's map
+ */
+func With(m map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// WithExtension copies hx with the htmx extension ext added to hx-ext,
+// keeping the extensions already listed
+func WithExtension(hx HtmxProps, ext string) HtmxProps {
+	value := ext
+	if existing := hx["ext"]; existing != "" {
+		value = existing
+		if !strings.Contains(","+strings.ReplaceAll(existing, " ", "")+",", ","+ext+",") {
+			value += ", " + ext
+		}
+	}
+	return With(hx, "ext", value)
+}
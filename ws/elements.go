@@ -0,0 +1,44 @@
+package ws
+
+// Ref: https://htmx.org/extensions/ws/
+
+import (
+	"html"
+
+	"github.com/bitpartio/Mx/elements"
+	"github.com/bitpartio/Mx/utils"
+)
+
+/*
+ * Connect adds the attributes opening a WebSocket from the element,
+ * hx-ext="ws" and ws-connect, to props. Descendants send with Send. The
+ * extension attributes carry a data- prefix, which htmx reads too.
+ */
+func Connect(props elements.GlobalProps, url string) elements.GlobalProps {
+	props.Htmx = utils.WithExtension(props.Htmx, "ws")
+	props.Data = utils.With(props.Data, "ws-connect", html.EscapeString(url))
+	return props
+}
+
+/*
+ * Send adds ws-send to props: a form sends its values when submitted,
+ * other elements on their hx-trigger. Give the element a name or ID for
+ * Hub.Handle to route by.
+ */
+func Send(props elements.GlobalProps) elements.GlobalProps {
+	props.Data = utils.With(props.Data, "ws-send", "")
+	return props
+}
+
+/*
+ * OOB marks the root of a pushed fragment with hx-swap-oob, swapping it
+ * into the element with the same ID: "true" replaces that element,
+ * others such as "beforeend" or "innerHTML" place the fragment's content
+ */
+func OOB(props elements.GlobalProps, swap string) elements.GlobalProps {
+	if swap == "" {
+		swap = "true"
+	}
+	props.Htmx = utils.With(props.Htmx, "swap-oob", html.EscapeString(swap))
+	return props
+}
@@ -0,0 +1,88 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+/*
+ * Request headers htmx adds to every ws-send message
+ */
+type Headers struct {
+	Request     string `json:"HX-Request"`
+	Trigger     string `json:"HX-Trigger"`
+	TriggerName string `json:"HX-Trigger-Name"`
+	Target      string `json:"HX-Target"`
+	CurrentURL  string `json:"HX-Current-URL"`
+}
+
+/*
+ * A message sent by a ws-send element: the values of its form, or of the
+ * element and its hx-vals, with the htmx headers
+ */
+type Message struct {
+	Values  url.Values
+	Headers Headers
+}
+
+// route is the handler key: the name of the triggering element, else its ID
+func (m Message) route() string {
+	if m.Headers.TriggerName != "" {
+		return m.Headers.TriggerName
+	}
+	return m.Headers.Trigger
+}
+
+/*
+ * parseMessage reads the JSON htmx sends, where values are strings, or
+ * arrays for repeated names, and anything hx-vals held
+ */
+func parseMessage(data []byte) (Message, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Message{}, err
+	}
+
+	m := Message{Values: url.Values{}}
+	if h, ok := raw["HEADERS"]; ok {
+		if err := json.Unmarshal(h, &m.Headers); err != nil {
+			return Message{}, err
+		}
+		delete(raw, "HEADERS")
+	}
+
+	for name, v := range raw {
+		var list []interface{}
+		if err := json.Unmarshal(v, &list); err != nil {
+			var one interface{}
+			if err := json.Unmarshal(v, &one); err != nil {
+				return Message{}, err
+			}
+			list = []interface{}{one}
+		}
+		for _, item := range list {
+			m.Values.Add(name, str(item))
+		}
+	}
+	return m, nil
+}
+
+func str(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,308 @@
+/*
+ * Package ws serves WebSocket connections for the htmx ws extension.
+ * Messages from ws-send elements are routed to Go handlers by the name or
+ * ID of the element that sent them; handlers push rendered fragments back
+ * to one client, a group or everyone, and htmx swaps them into the page by
+ * ID (see OOB).
+ *
+ *	hub := ws.New(ws.Options{})
+ *	hub.Handle("chat", func(c *ws.Client, m ws.Message) error {
+ *		hub.Group("room:"+m.Values.Get("room"), Ul(UlProps{
+ *			GlobalProps: ws.OOB(GlobalProps{ID: "messages"}, "beforeend"),
+ *			InnerHTML:   Li(LiProps{InnerHTML: html.EscapeString(m.Values.Get("text"))}),
+ *		}))
+ *		return nil
+ *	})
+ *	http.Handle("/ws", hub)
+ *
+ *	// On the page
+ *	Div(DivProps{GlobalProps: ws.Connect(GlobalProps{}, "/ws"),
+ *		InnerHTML: Form(FormProps{GlobalProps: ws.Send(GlobalProps{}), ...})})
+ */
+package ws
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	DefaultBuffer     = 16
+	DefaultMaxMessage = 1 << 20
+)
+
+// ErrClosed is returned when sending to a closed client
+var ErrClosed = errors.New("ws: client closed")
+
+// Handler responds to messages. An error closes the client's connection.
+type Handler func(c *Client, m Message) error
+
+/*
+ * Options
+ */
+type Options struct {
+	// Messages queued per client; a client falling further behind is
+	// disconnected. DefaultBuffer when zero.
+	Buffer int
+	// Largest message accepted, DefaultMaxMessage when zero
+	MaxMessage int
+	// Accepts the handshake; when nil, only pages from the request's own
+	// host may connect, keeping other sites from borrowing the user's
+	// cookies
+	CheckOrigin func(r *http.Request) bool
+	// Called for each new client, e.g. to Join groups
+	OnConnect func(c *Client)
+	// Called once a client has gone
+	OnDisconnect func(c *Client)
+}
+
+/*
+ * Hub accepts connections, routes their messages and broadcasts to them.
+ * Create one with New.
+ */
+type Hub struct {
+	opts Options
+
+	mu       sync.RWMutex
+	routes   map[string]Handler
+	fallback Handler
+	clients  map[*Client]struct{}
+	groups   map[string]map[*Client]struct{}
+}
+
+// New
+func New(opts Options) *Hub {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBuffer
+	}
+	if opts.MaxMessage <= 0 {
+		opts.MaxMessage = DefaultMaxMessage
+	}
+	return &Hub{
+		opts:    opts,
+		routes:  map[string]Handler{},
+		clients: map[*Client]struct{}{},
+		groups:  map[string]map[*Client]struct{}{},
+	}
+}
+
+/*
+ * Handle routes messages from elements with the name, or else the ID,
+ * route. The empty route receives unrouted messages.
+ */
+func (h *Hub) Handle(route string, fn Handler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if route == "" {
+		h.fallback = fn
+		return
+	}
+	h.routes[route] = fn
+}
+
+func (h *Hub) handler(route string) Handler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if fn, ok := h.routes[route]; ok {
+		return fn
+	}
+	return h.fallback
+}
+
+// Broadcast sends html to every client
+func (h *Hub) Broadcast(html string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.clients {
+		c.Send(html)
+	}
+}
+
+// Group sends html to the clients that joined group
+func (h *Hub) Group(group, html string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.groups[group] {
+		c.Send(html)
+	}
+}
+
+// Clients counts the connected clients
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if h.opts.CheckOrigin != nil {
+				if !h.opts.CheckOrigin(r) {
+					return errors.New("ws: origin not allowed")
+				}
+				return nil
+			}
+			return sameOrigin(r)
+		},
+		Handler: func(conn *websocket.Conn) {
+			h.serve(conn, r)
+		},
+	}.ServeHTTP(w, r)
+}
+
+func sameOrigin(r *http.Request) error {
+	u, err := url.Parse(r.Header.Get("Origin"))
+	if err != nil || !strings.EqualFold(u.Host, r.Host) {
+		return errors.New("ws: cross-origin connection")
+	}
+	return nil
+}
+
+func (h *Hub) serve(conn *websocket.Conn, r *http.Request) {
+	conn.MaxPayloadBytes = h.opts.MaxMessage
+	c := &Client{
+		Request: r,
+		hub:     h,
+		conn:    conn,
+		send:    make(chan string, h.opts.Buffer),
+		done:    make(chan struct{}),
+		groups:  map[string]struct{}{},
+	}
+
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+
+	go c.write()
+	if h.opts.OnConnect != nil {
+		h.opts.OnConnect(c)
+	}
+	c.read()
+
+	c.Close()
+	h.mu.Lock()
+	delete(h.clients, c)
+	for group := range c.groups {
+		h.leave(c, group)
+	}
+	h.mu.Unlock()
+	if h.opts.OnDisconnect != nil {
+		h.opts.OnDisconnect(c)
+	}
+}
+
+// leave removes c from group, with h.mu held
+func (h *Hub) leave(c *Client, group string) {
+	delete(c.groups, group)
+	delete(h.groups[group], c)
+	if len(h.groups[group]) == 0 {
+		delete(h.groups, group)
+	}
+}
+
+/*
+ * A connected browser
+ */
+type Client struct {
+	// The upgrade request, carrying what middleware put in its context
+	Request *http.Request
+
+	hub    *Hub
+	conn   *websocket.Conn
+	send   chan string
+	once   sync.Once
+	done   chan struct{}
+	groups map[string]struct{} // Guarded by hub.mu
+}
+
+/*
+ * Send queues html for the client, closing the connection when its queue
+ * is full
+ */
+func (c *Client) Send(html string) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case c.send <- html:
+		return nil
+	case <-c.done:
+		return ErrClosed
+	default:
+		c.Close()
+		return ErrClosed
+	}
+}
+
+// Join adds the client to group
+func (c *Client) Join(group string) {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[c]; !ok {
+		return
+	}
+	if h.groups[group] == nil {
+		h.groups[group] = map[*Client]struct{}{}
+	}
+	h.groups[group][c] = struct{}{}
+	c.groups[group] = struct{}{}
+}
+
+// Leave removes the client from group
+func (c *Client) Leave(group string) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	c.hub.leave(c, group)
+}
+
+// Close disconnects the client
+func (c *Client) Close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+func (c *Client) read() {
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(c.conn, &data); err != nil {
+			return
+		}
+		m, err := parseMessage(data)
+		if err != nil {
+			return
+		}
+		fn := c.hub.handler(m.route())
+		if fn == nil {
+			continue
+		}
+		if err := fn(c, m); err != nil {
+			return
+		}
+	}
+}
+
+func (c *Client) write() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case html := <-c.send:
+			if err := websocket.Message.Send(c.conn, html); err != nil {
+				c.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package ws
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestHub(t *testing.T) {
+	hub := New(Options{OnConnect: func(c *Client) { c.Join("room") }})
+	hub.Handle("chat", func(c *Client, m Message) error {
+		hub.Group("room", `<li id="last" hx-swap-oob="true">`+m.Values.Get("text")+`</li>`)
+		return nil
+	})
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	if _, err := websocket.Dial(url, "", "http://evil.example"); err == nil {
+		t.Fatal("cross-origin connection accepted")
+	}
+
+	conn, err := websocket.Dial(url, "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	msg := `{"text":"hi","tags":["a","b"],"HEADERS":{"HX-Request":"true","HX-Trigger-Name":"chat"}}`
+	if err := websocket.Message.Send(conn, msg); err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	if err := websocket.Message.Receive(conn, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != `<li id="last" hx-swap-oob="true">hi</li>` {
+		t.Fatalf("unexpected %q", got)
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	m, err := parseMessage([]byte(`{"n":1.5,"tags":["a","b"],"HEADERS":{"HX-Trigger":"form-id"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Values.Get("n") != "1.5" || len(m.Values["tags"]) != 2 || m.route() != "form-id" {
+		t.Fatalf("unexpected %+v", m)
+	}
+}